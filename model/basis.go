@@ -0,0 +1,17 @@
+package model
+
+// Basis is a simplex basis over the columns of the slack form: the columns of the variables, including the
+// negative parts of split free variables, followed by one slack column per row. It can be saved after a solve
+// and passed back to warm start the solve of a modified problem with the same slack form shape.
+type Basis struct {
+	BasicVariables []int  `json:"basicVariables"` // Basic column of each row
+	AtUpper        []bool `json:"atUpper"`        // Nonbasic columns sitting at their upper bound instead of 0
+}
+
+// Clone returns a deep copy of the basis.
+func (basis *Basis) Clone() *Basis {
+	return &Basis{
+		BasicVariables: append([]int(nil), basis.BasicVariables...),
+		AtUpper:        append([]bool(nil), basis.AtUpper...),
+	}
+}
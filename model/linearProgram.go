@@ -2,7 +2,9 @@ package model
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 )
 
 // Enums for Objective and Comparison
@@ -23,6 +25,24 @@ const (
 	BE
 )
 
+// Sign restricts the sign of a variable.
+type Sign int
+
+const (
+	NonNegative Sign = iota
+	NonPositive
+	Free
+)
+
+// VariableType restricts the values of a variable to integers.
+type VariableType int
+
+const (
+	Continuous VariableType = iota
+	Integer
+	Binary // Integer within [0, 1]
+)
+
 type LPState int
 
 const (
@@ -41,22 +61,332 @@ type LinearProgram struct {
 	ObjVar              []float64
 	ObjCoeff            []float64
 	Comparisons         []Comparison
-	ConstraintCoeff     [][]float64
+	ConstraintCoeff     *SparseMatrix
 	Rhs                 []float64
+	ConstraintTexts     []string       // Source text of each constraint, set by the parser
+	Signs               []Sign         // Sign of each variable, nil means NonNegative for all of them
+	Types               []VariableType // Type of each variable, nil means Continuous for all of them
+	LowerBounds         []float64      // Lower bound of each variable, nil means the bound implied by its sign
+	UpperBounds         []float64      // Upper bound of each variable, nil means the bound implied by its sign
+	ObjConstant         float64        // Constant term of the objective function
+	VariableOffsets     []float64      // Values removed from the variables by ShiftLowerBounds
+	MirroredVariables   []bool         // Variables substituted by x' = -x by SplitFreeVariables
+	SplitVariables      []int          // Original variable of each negative part column added by SplitFreeVariables
+	SplitVariablesNames []string
+	NegatedObjective    bool      // Set by EnsureMaximization when it turned a MINIMIZE objective around
+	RowOrigins          []int     // Original constraint of each row, tracked by the conversions
+	RowSigns            []float64 // Each row is its original constraint multiplied by this sign
+	Sensitivity         *Sensitivity
+	Duals               []float64 // Shadow price of each original constraint: change of the optimum per unit of RHS
+	Slacks              []float64 // Slack (<=) or surplus (>=) of each original constraint
+	ReducedCosts        []float64 // c_j - y*A_j of each original variable, in the sense of the original objective
+	Basis               *Basis    // Final basis of the last simplex solve
+	AlternativeOptima   bool      // Set by the simplex when another solution reaches the same optimal objective value
 	State               LPState
 }
 
+// Clone returns a deep copy of the linear program, which stays untouched when the copy is converted or solved.
+func (lp *LinearProgram) Clone() *LinearProgram {
+	clone := *lp
+	clone.VariableNames = append([]string(nil), lp.VariableNames...)
+	clone.SlackVariablesNames = append([]string(nil), lp.SlackVariablesNames...)
+	clone.ObjVar = append([]float64(nil), lp.ObjVar...)
+	clone.ObjCoeff = append([]float64(nil), lp.ObjCoeff...)
+	clone.Comparisons = append([]Comparison(nil), lp.Comparisons...)
+	if lp.ConstraintCoeff != nil {
+		clone.ConstraintCoeff = lp.ConstraintCoeff.Clone()
+	}
+	clone.Rhs = append([]float64(nil), lp.Rhs...)
+	clone.ConstraintTexts = append([]string(nil), lp.ConstraintTexts...)
+	clone.Signs = append([]Sign(nil), lp.Signs...)
+	clone.Types = append([]VariableType(nil), lp.Types...)
+	clone.LowerBounds = append([]float64(nil), lp.LowerBounds...)
+	clone.UpperBounds = append([]float64(nil), lp.UpperBounds...)
+	clone.VariableOffsets = append([]float64(nil), lp.VariableOffsets...)
+	clone.MirroredVariables = append([]bool(nil), lp.MirroredVariables...)
+	clone.SplitVariables = append([]int(nil), lp.SplitVariables...)
+	clone.SplitVariablesNames = append([]string(nil), lp.SplitVariablesNames...)
+	clone.RowOrigins = append([]int(nil), lp.RowOrigins...)
+	clone.RowSigns = append([]float64(nil), lp.RowSigns...)
+	if lp.Sensitivity != nil {
+		clone.Sensitivity = &Sensitivity{
+			ObjCoeff: append([]Range(nil), lp.Sensitivity.ObjCoeff...),
+			Rhs:      append([]Range(nil), lp.Sensitivity.Rhs...),
+		}
+	}
+	clone.Duals = append([]float64(nil), lp.Duals...)
+	clone.Slacks = append([]float64(nil), lp.Slacks...)
+	clone.ReducedCosts = append([]float64(nil), lp.ReducedCosts...)
+	if lp.Basis != nil {
+		clone.Basis = lp.Basis.Clone()
+	}
+	return &clone
+}
+
+// VariableName returns the name of the variable at the given index, or x1, x2, ... if it has none.
+func (lp *LinearProgram) VariableName(index int) string {
+	if index < len(lp.VariableNames) {
+		return lp.VariableNames[index]
+	}
+	return fmt.Sprintf("x%d", index+1)
+}
+
+// SignBounds returns the lower and upper bound implied by a sign restriction.
+func SignBounds(sign Sign) (float64, float64) {
+	switch sign {
+	case NonPositive:
+		return math.Inf(-1), 0
+	case Free:
+		return math.Inf(-1), math.Inf(1)
+	default:
+		return 0, math.Inf(1)
+	}
+}
+
+// Bounds returns the lower and upper bound of the variable at the given index.
+// The bounds of a binary variable are restricted to [0, 1].
+func (lp *LinearProgram) Bounds(index int) (float64, float64) {
+	lower, upper := 0.0, math.Inf(1)
+	if lp.Signs != nil {
+		lower, upper = SignBounds(lp.Signs[index])
+	}
+	if lp.LowerBounds != nil {
+		lower = lp.LowerBounds[index]
+	}
+	if lp.UpperBounds != nil {
+		upper = lp.UpperBounds[index]
+	}
+	if index < len(lp.Types) && lp.Types[index] == Binary {
+		lower, upper = math.Max(lower, 0), math.Min(upper, 1)
+	}
+	return lower, upper
+}
+
+// IsInteger reports whether the variable at the given index must take an integer value.
+func (lp *LinearProgram) IsInteger(index int) bool {
+	return index < len(lp.Types) && lp.Types[index] != Continuous
+}
+
+// ErrCrossedBounds is returned by CheckBounds for a variable whose lower bound is above its upper bound,
+// which makes the problem infeasible.
+var ErrCrossedBounds = errors.New("infeasible problem")
+
+// CheckBounds returns an error if the lower bound of a variable is above its upper bound.
+func (lp *LinearProgram) CheckBounds() error {
+	for j := 0; j < lp.NbVariables; j++ {
+		lower, upper := lp.Bounds(j)
+		if math.IsNaN(lower) || math.IsNaN(upper) {
+			return fmt.Errorf("invalid bounds for variable %s", lp.VariableName(j))
+		}
+		if lower > upper {
+			return ErrCrossedBounds
+		}
+	}
+	return nil
+}
+
+// AddConstraint appends the constraint coeffs*x comparison rhs to a problem that is not converted yet.
+// The text is recorded in ConstraintTexts if the problem keeps the source text of its constraints.
+func (lp *LinearProgram) AddConstraint(coeffs []float64, comparison Comparison, rhs float64, text string) {
+	lp.ConstraintCoeff.AddRow(coeffs)
+	lp.Comparisons = append(lp.Comparisons, comparison)
+	lp.Rhs = append(lp.Rhs, rhs)
+	if lp.ConstraintTexts != nil {
+		lp.ConstraintTexts = append(lp.ConstraintTexts, text)
+	}
+	lp.NbConstraints++
+}
+
+// OriginalValues maps the values of the columns of the canonical form back to the original variables,
+// undoing ShiftLowerBounds and SplitFreeVariables.
+func (lp *LinearProgram) OriginalValues(values []float64) []float64 {
+	numOrigVars := lp.NbVariables - len(lp.SplitVariables) - len(lp.SlackVariablesNames)
+
+	shifted := make([]float64, numOrigVars+len(lp.SplitVariables))
+	copy(shifted, values)
+	for j := range shifted {
+		if j < len(lp.VariableOffsets) {
+			shifted[j] += lp.VariableOffsets[j]
+		}
+	}
+
+	original := shifted[:numOrigVars]
+	for j := range original {
+		if lp.MirroredVariables != nil && lp.MirroredVariables[j] {
+			original[j] *= -1
+		}
+	}
+	for k, j := range lp.SplitVariables {
+		original[j] -= shifted[numOrigVars+k]
+	}
+
+	return original
+}
+
+// OriginalColumns returns the columns of the canonical form that make up the original variable j,
+// with their signs: x_j = offset + sum of sign * column.
+func (lp *LinearProgram) OriginalColumns(j int) ([]int, []float64) {
+	numOrigVars := lp.NbVariables - len(lp.SplitVariables) - len(lp.SlackVariablesNames)
+
+	sign := 1.0
+	if lp.MirroredVariables != nil && lp.MirroredVariables[j] {
+		sign = -1
+	}
+	columns, signs := []int{j}, []float64{sign}
+	for k, original := range lp.SplitVariables {
+		if original == j {
+			columns = append(columns, numOrigVars+k)
+			signs = append(signs, -1)
+		}
+	}
+	return columns, signs
+}
+
+// OriginalRows returns the rows that come from the original constraint i, with the sign each one was multiplied by.
+func (lp *LinearProgram) OriginalRows(i int) ([]int, []float64) {
+	if lp.RowOrigins == nil {
+		return []int{i}, []float64{1}
+	}
+	var rows []int
+	var signs []float64
+	for row, origin := range lp.RowOrigins {
+		if origin == i {
+			rows = append(rows, row)
+			signs = append(signs, lp.RowSigns[row])
+		}
+	}
+	return rows, signs
+}
+
+// NbOriginalConstraints returns the number of constraints before the conversions.
+func (lp *LinearProgram) NbOriginalConstraints() int {
+	if lp.RowOrigins == nil {
+		return lp.NbConstraints
+	}
+	count := 0
+	for _, origin := range lp.RowOrigins {
+		if origin >= count {
+			count = origin + 1
+		}
+	}
+	return count
+}
+
+// OriginalObjCoeff returns the objective coefficient of the original variable j.
+func (lp *LinearProgram) OriginalObjCoeff(j int) float64 {
+	columns, signs := lp.OriginalColumns(j)
+	coeff := signs[0] * lp.ObjCoeff[columns[0]]
+	if lp.NegatedObjective {
+		coeff *= -1
+	}
+	return coeff
+}
+
+// OriginalRhs returns the right-hand side of the original constraint i, undoing ShiftLowerBounds
+// and the negations of its row.
+func (lp *LinearProgram) OriginalRhs(i int) float64 {
+	rows, signs := lp.OriginalRows(i)
+	row := rows[0]
+	rhs := lp.Rhs[row]
+	coeffs := lp.ConstraintCoeff.Rows[row]
+	for k, j := range coeffs.Indices {
+		if j < len(lp.VariableOffsets) {
+			rhs += coeffs.Values[k] * lp.VariableOffsets[j]
+		}
+	}
+	return signs[0] * rhs
+}
+
+// OriginalMultipliers maps values attached to the rows of the converted problem to the original constraints,
+// summing the values of the rows of each constraint with the sign the row was multiplied by.
+func (lp *LinearProgram) OriginalMultipliers(rowValues []float64) []float64 {
+	multipliers := make([]float64, lp.NbOriginalConstraints())
+	for i := range multipliers {
+		rows, signs := lp.OriginalRows(i)
+		for k, row := range rows {
+			multipliers[i] += signs[k] * rowValues[row]
+		}
+	}
+	return multipliers
+}
+
+// OriginalDirection maps a direction over the columns of the canonical form to the original variables.
+// Unlike OriginalValues it ignores the offsets of ShiftLowerBounds, which do not move a direction.
+func (lp *LinearProgram) OriginalDirection(columnValues []float64) []float64 {
+	numOrigVars := lp.NbVariables - len(lp.SplitVariables) - len(lp.SlackVariablesNames)
+	direction := make([]float64, numOrigVars)
+	for j := range direction {
+		columns, signs := lp.OriginalColumns(j)
+		for k, col := range columns {
+			direction[j] += signs[k] * columnValues[col]
+		}
+	}
+	return direction
+}
+
+// SetDuals maps the duals of the rows of the slack form, for the maximized objective, and the values of its
+// structural columns to the duals and slacks of the original constraints and the reduced costs of the original variables.
+func (lp *LinearProgram) SetDuals(rowDuals, values []float64) {
+	objectiveSign := 1.0
+	if lp.NegatedObjective {
+		objectiveSign = -1
+	}
+
+	lp.Duals = lp.OriginalMultipliers(rowDuals)
+	lp.Slacks = make([]float64, lp.NbOriginalConstraints())
+	for i := range lp.Duals {
+		lp.Duals[i] *= objectiveSign
+		rows, _ := lp.OriginalRows(i)
+		// Every row is turned into a <= row, whose slack is the slack or surplus of the original constraint
+		row := lp.ConstraintCoeff.Rows[rows[0]]
+		lp.Slacks[i] = lp.Rhs[rows[0]]
+		for k, j := range row.Indices {
+			if j < len(values) {
+				lp.Slacks[i] -= row.Values[k] * values[j]
+			}
+		}
+	}
+
+	columns := lp.ConstraintCoeff.Transpose()
+	numOrigVars := lp.NbVariables - len(lp.SplitVariables) - len(lp.SlackVariablesNames)
+	lp.ReducedCosts = make([]float64, numOrigVars)
+	for j := range lp.ReducedCosts {
+		cols, signs := lp.OriginalColumns(j)
+		col := cols[0]
+		reducedCost := lp.ObjCoeff[col] - columns.Rows[col].Dot(rowDuals)
+		lp.ReducedCosts[j] = objectiveSign * signs[0] * reducedCost
+	}
+}
+
 // GetSolutionJSON returns the solution of the linear program in JSON format.
+// ObjVar holds the values of the original variables, split and mirrored variables are already mapped back.
+// The duals, slacks, reduced costs and sensitivity ranges are included when they are available, and after a
+// simplex solve uniqueOptimum tells whether the solution is the only optimal one.
 func (lp *LinearProgram) GetSolutionJSON() (string, error) {
 	if lp.ObjVar == nil {
 		return "", fmt.Errorf("solution not available")
 	}
 
-	solution := make(map[string]float64)
+	solution := make(map[string]interface{})
 	for i, name := range lp.VariableNames {
 		solution[name] = lp.ObjVar[i]
 	}
 	solution["objective"] = lp.ObjVar[len(lp.ObjVar)-1]
+	if lp.Duals != nil {
+		reducedCosts := make(map[string]float64)
+		for j, reducedCost := range lp.ReducedCosts {
+			reducedCosts[lp.VariableName(j)] = reducedCost
+		}
+		solution["duals"] = lp.Duals
+		solution["slacks"] = lp.Slacks
+		solution["reducedCosts"] = reducedCosts
+	}
+	if lp.Sensitivity != nil {
+		solution["sensitivity"] = lp.sensitivityJSON()
+	}
+	if lp.Basis != nil {
+		solution["uniqueOptimum"] = !lp.AlternativeOptima
+	}
 
 	jsonBytes, err := json.Marshal(solution)
 	if err != nil {
@@ -1,12 +1,17 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 // ToCanonicalForm converts the linear program to the canonical form (<= constraints).
 func (lp *LinearProgram) ToCanonicalForm() {
 	if lp.State != Undefined {
 		return
 	}
+	lp.SplitFreeVariables()
+	lp.ShiftLowerBounds()
 	lp.EnsureMaximization()
 	lp.EnsureNonNegativeRhs()
 	lp.ConvertToLeConstraints()
@@ -31,64 +36,189 @@ func (lp *LinearProgram) EnsureMaximization() {
 		for i := range lp.ObjCoeff {
 			lp.ObjCoeff[i] *= -1
 		}
+		lp.ObjConstant *= -1
+		lp.NegatedObjective = true
+	}
+}
+
+// SplitFreeVariables removes the variables without a finite lower bound. A variable with a finite
+// upper bound u is substituted by x' = -x >= -u, a free variable by x = x+ - x- with a new column for x-.
+// Afterwards the bounds are explicit and every variable is non-negative up to ShiftLowerBounds.
+func (lp *LinearProgram) SplitFreeVariables() {
+	if lp.Signs == nil && lp.LowerBounds == nil {
+		return
+	}
+	lp.materializeBounds()
+	lp.Signs = nil
+
+	// Negate the constraint coefficients of every mirrored or split variable in a single pass over the rows
+	numOrigVars := lp.NbVariables
+	negatedColumns := make([]int, numOrigVars) // column receiving the negated coefficients, -1 for none
+	for j := 0; j < numOrigVars; j++ {
+		negatedColumns[j] = -1
+		if !math.IsInf(lp.LowerBounds[j], -1) {
+			continue
+		}
+
+		if !math.IsInf(lp.UpperBounds[j], 1) {
+			if lp.MirroredVariables == nil {
+				lp.MirroredVariables = make([]bool, numOrigVars)
+			}
+			lp.MirroredVariables[j] = true
+			negatedColumns[j] = j
+			lp.ObjCoeff[j] *= -1
+			lp.LowerBounds[j], lp.UpperBounds[j] = -lp.UpperBounds[j], math.Inf(1)
+			continue
+		}
+
+		lp.NbVariables++
+		lp.SplitVariables = append(lp.SplitVariables, j)
+		lp.SplitVariablesNames = append(lp.SplitVariablesNames, lp.VariableName(j)+"_neg")
+		lp.ObjCoeff = append(lp.ObjCoeff, -lp.ObjCoeff[j])
+		negatedColumns[j] = lp.ConstraintCoeff.AddColumn()
+		lp.LowerBounds[j] = 0
+		lp.appendDefaultBounds()
+	}
+
+	for i := range lp.ConstraintCoeff.Rows {
+		row := &lp.ConstraintCoeff.Rows[i]
+		for k, j := range row.Indices {
+			switch col := negatedColumns[j]; {
+			case col == j:
+				row.Values[k] *= -1
+			case col != -1:
+				// The new columns come after the original ones in the order of their variables, so the row stays sorted
+				row.Indices = append(row.Indices, col)
+				row.Values = append(row.Values, -row.Values[k])
+			}
+		}
+	}
+}
+
+// materializeBounds fills LowerBounds and UpperBounds, so that they no longer depend on Signs.
+func (lp *LinearProgram) materializeBounds() {
+	lowerBounds := make([]float64, lp.NbVariables)
+	upperBounds := make([]float64, lp.NbVariables)
+	for j := 0; j < lp.NbVariables; j++ {
+		lowerBounds[j], upperBounds[j] = lp.Bounds(j)
+	}
+	lp.LowerBounds = lowerBounds
+	lp.UpperBounds = upperBounds
+}
+
+// ShiftLowerBounds substitutes x = l + x' for every variable with a non-zero lower bound l,
+// so that all variables are bounded below by 0. The removed values are kept in VariableOffsets.
+func (lp *LinearProgram) ShiftLowerBounds() {
+	if lp.LowerBounds == nil {
+		return
+	}
+	if lp.VariableOffsets == nil {
+		lp.VariableOffsets = make([]float64, lp.NbVariables)
+	}
+
+	for i, row := range lp.ConstraintCoeff.Rows {
+		lp.Rhs[i] -= row.Dot(lp.LowerBounds)
+	}
+	for j, lower := range lp.LowerBounds {
+		if lower == 0 {
+			continue
+		}
+		lp.ObjConstant += lp.ObjCoeff[j] * lower
+		if lp.UpperBounds != nil {
+			lp.UpperBounds[j] -= lower
+		}
+		lp.VariableOffsets[j] += lower
+		lp.LowerBounds[j] = 0
 	}
 }
 
 func (lp *LinearProgram) EnsureNonNegativeRhs() {
+	lp.trackRows()
 	for i := range lp.Rhs {
 		if lp.Rhs[i] < 0 {
 			lp.Rhs[i] *= -1
-			lp.ConstraintCoeff[i] = MultiplyRow(lp.ConstraintCoeff[i], -1)
+			lp.ConstraintCoeff.Rows[i] = lp.ConstraintCoeff.Rows[i].Scale(-1)
 			lp.Comparisons[i] = FlipComparison(lp.Comparisons[i])
+			lp.RowSigns[i] *= -1
 		}
 	}
 }
 
+// trackRows starts tracking the original constraint of each row, if it is not tracked yet.
+func (lp *LinearProgram) trackRows() {
+	if lp.RowOrigins != nil {
+		return
+	}
+	lp.RowOrigins = make([]int, lp.NbConstraints)
+	lp.RowSigns = make([]float64, lp.NbConstraints)
+	for i := range lp.RowOrigins {
+		lp.RowOrigins[i] = i
+		lp.RowSigns[i] = 1
+	}
+}
+
 func (lp *LinearProgram) ConvertToLeConstraints() {
-	var newConstraintCoeff [][]float64
+	var newConstraintCoeff []SparseVector
 	var newRhs []float64
 	var newComparisons []Comparison
+	var newRowOrigins []int
+	var newRowSigns []float64
 	newNbConstraints := 0
 
+	lp.trackRows()
 	for i := 0; i < lp.NbConstraints; i++ {
 		switch lp.Comparisons[i] {
 		case LE:
-			newConstraintCoeff = append(newConstraintCoeff, lp.ConstraintCoeff[i])
+			newConstraintCoeff = append(newConstraintCoeff, lp.ConstraintCoeff.Rows[i])
+			newRowOrigins = append(newRowOrigins, lp.RowOrigins[i])
+			newRowSigns = append(newRowSigns, lp.RowSigns[i])
 			newRhs = append(newRhs, lp.Rhs[i])
 			newComparisons = append(newComparisons, LE)
 			newNbConstraints++
 		case LO:
-			newConstraintCoeff = append(newConstraintCoeff, lp.ConstraintCoeff[i])
+			newConstraintCoeff = append(newConstraintCoeff, lp.ConstraintCoeff.Rows[i])
+			newRowOrigins = append(newRowOrigins, lp.RowOrigins[i])
+			newRowSigns = append(newRowSigns, lp.RowSigns[i])
 			newRhs = append(newRhs, lp.Rhs[i])
 			newComparisons = append(newComparisons, LE)
 			newNbConstraints++
 		case BE:
-			newConstraintCoeff = append(newConstraintCoeff, MultiplyRow(lp.ConstraintCoeff[i], -1))
+			newConstraintCoeff = append(newConstraintCoeff, lp.ConstraintCoeff.Rows[i].Scale(-1))
+			newRowOrigins = append(newRowOrigins, lp.RowOrigins[i])
+			newRowSigns = append(newRowSigns, -lp.RowSigns[i])
 			newRhs = append(newRhs, -lp.Rhs[i])
 			newComparisons = append(newComparisons, LE)
 			newNbConstraints++
 		case BI:
-			newConstraintCoeff = append(newConstraintCoeff, MultiplyRow(lp.ConstraintCoeff[i], -1))
+			newConstraintCoeff = append(newConstraintCoeff, lp.ConstraintCoeff.Rows[i].Scale(-1))
+			newRowOrigins = append(newRowOrigins, lp.RowOrigins[i])
+			newRowSigns = append(newRowSigns, -lp.RowSigns[i])
 			newRhs = append(newRhs, -lp.Rhs[i])
 			newComparisons = append(newComparisons, LE)
 			newNbConstraints++
 		case EQ:
 			// Add <= constraint
-			newConstraintCoeff = append(newConstraintCoeff, lp.ConstraintCoeff[i])
+			newConstraintCoeff = append(newConstraintCoeff, lp.ConstraintCoeff.Rows[i])
+			newRowOrigins = append(newRowOrigins, lp.RowOrigins[i])
+			newRowSigns = append(newRowSigns, lp.RowSigns[i])
 			newRhs = append(newRhs, lp.Rhs[i])
 			newComparisons = append(newComparisons, LE)
 			newNbConstraints++
 
 			// Add >= constraint, which is then converted to <=
-			newConstraintCoeff = append(newConstraintCoeff, MultiplyRow(lp.ConstraintCoeff[i], -1))
+			newConstraintCoeff = append(newConstraintCoeff, lp.ConstraintCoeff.Rows[i].Scale(-1))
+			newRowOrigins = append(newRowOrigins, lp.RowOrigins[i])
+			newRowSigns = append(newRowSigns, -lp.RowSigns[i])
 			newRhs = append(newRhs, -lp.Rhs[i])
 			newComparisons = append(newComparisons, LE)
 			newNbConstraints++
 		}
 	}
-	lp.ConstraintCoeff = newConstraintCoeff
+	lp.ConstraintCoeff.Rows = newConstraintCoeff
 	lp.Rhs = newRhs
 	lp.Comparisons = newComparisons
+	lp.RowOrigins = newRowOrigins
+	lp.RowSigns = newRowSigns
 	lp.NbConstraints = newNbConstraints
 }
 
@@ -109,36 +239,38 @@ func (lp *LinearProgram) ConvertToEqualities() {
 
 func (lp *LinearProgram) AddSlackVariable(constraintIndex int) {
 	lp.NbVariables++
+	lp.appendDefaultBounds()
 	newVarName := fmt.Sprintf("s%d", len(lp.SlackVariablesNames)+1)
 	lp.SlackVariablesNames = append(lp.SlackVariablesNames, newVarName)
 	lp.ObjCoeff = append(lp.ObjCoeff, 0)
 
-	for i := range lp.ConstraintCoeff {
-		newCol := 0.0
-		if i == constraintIndex {
-			newCol = 1.0
-		}
-		lp.ConstraintCoeff[i] = append(lp.ConstraintCoeff[i], newCol)
-	}
+	col := lp.ConstraintCoeff.AddColumn()
+	lp.ConstraintCoeff.Set(constraintIndex, col, 1)
 	lp.Comparisons[constraintIndex] = EQ
 }
 
 func (lp *LinearProgram) AddSurplusVariable(constraintIndex int) {
 	lp.NbVariables++
+	lp.appendDefaultBounds()
 	newVarName := fmt.Sprintf("s%d", len(lp.SlackVariablesNames)+1)
 	lp.SlackVariablesNames = append(lp.SlackVariablesNames, newVarName)
 	lp.ObjCoeff = append(lp.ObjCoeff, 0)
 
-	for i := range lp.ConstraintCoeff {
-		newCol := 0.0
-		if i == constraintIndex {
-			newCol = -1.0
-		}
-		lp.ConstraintCoeff[i] = append(lp.ConstraintCoeff[i], newCol)
-	}
+	col := lp.ConstraintCoeff.AddColumn()
+	lp.ConstraintCoeff.Set(constraintIndex, col, -1)
 	lp.Comparisons[constraintIndex] = EQ
 }
 
+// appendDefaultBounds gives a new variable the default bounds 0 <= x < +Inf.
+func (lp *LinearProgram) appendDefaultBounds() {
+	if lp.LowerBounds != nil {
+		lp.LowerBounds = append(lp.LowerBounds, 0)
+	}
+	if lp.UpperBounds != nil {
+		lp.UpperBounds = append(lp.UpperBounds, math.Inf(1))
+	}
+}
+
 func MultiplyRow(row []float64, scalar float64) []float64 {
 	newRow := make([]float64, len(row))
 	for i, val := range row {
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"reflect"
+	"sort"
 	"testing"
 )
 
@@ -12,7 +14,7 @@ func TestToCanonicalForm(t *testing.T) {
 		Objective:     MINIMIZE,
 		ObjCoeff:      []float64{1, 2},
 		Comparisons:   []Comparison{BE, LE},
-		ConstraintCoeff: [][]float64{{1, 1}, {2, 1}},
+		ConstraintCoeff: NewSparseMatrixFromDense([][]float64{{1, 1}, {2, 1}}),
 		Rhs:           []float64{10, 15},
 	}
 
@@ -30,8 +32,8 @@ func TestToCanonicalForm(t *testing.T) {
 		t.Errorf("Expected all comparisons to be LE, but got %v", lp.Comparisons)
 	}
 
-	if lp.ConstraintCoeff[0][0] != -1 || lp.ConstraintCoeff[0][1] != -1 || lp.Rhs[0] != -10 {
-		t.Errorf("Expected first constraint to be flipped, but got ConstraintCoeff: %v, Rhs: %v", lp.ConstraintCoeff[0], lp.Rhs[0])
+	if lp.ConstraintCoeff.At(0, 0) != -1 || lp.ConstraintCoeff.At(0, 1) != -1 || lp.Rhs[0] != -10 {
+		t.Errorf("Expected first constraint to be flipped, but got ConstraintCoeff: %v, Rhs: %v", lp.ConstraintCoeff.Dense()[0], lp.Rhs[0])
 	}
 }
 
@@ -43,7 +45,7 @@ func TestToSlackForm(t *testing.T) {
 		Objective:     MAXIMIZE,
 		ObjCoeff:      []float64{1, 2},
 		Comparisons:   []Comparison{LE, LE},
-		ConstraintCoeff: [][]float64{{1, 1}, {2, 1}},
+		ConstraintCoeff: NewSparseMatrixFromDense([][]float64{{1, 1}, {2, 1}}),
 		Rhs:           []float64{10, 15},
 	}
 
@@ -65,7 +67,158 @@ func TestToSlackForm(t *testing.T) {
 		t.Errorf("Expected all comparisons to be EQ, but got %v", lp.Comparisons)
 	}
 
-	if lp.ConstraintCoeff[0][2] != 1 || lp.ConstraintCoeff[1][3] != 1 {
-		t.Errorf("Expected slack variables to be added correctly, but got ConstraintCoeff: %v", lp.ConstraintCoeff)
+	if lp.ConstraintCoeff.At(0, 2) != 1 || lp.ConstraintCoeff.At(1, 3) != 1 {
+		t.Errorf("Expected slack variables to be added correctly, but got ConstraintCoeff: %v", lp.ConstraintCoeff.Dense())
+	}
+}
+
+func TestShiftLowerBounds(t *testing.T) {
+	lp := &LinearProgram{
+		NbConstraints:   1,
+		NbVariables:     2,
+		VariableNames:   []string{"x", "y"},
+		Objective:       MAXIMIZE,
+		ObjCoeff:        []float64{3, 1},
+		Comparisons:     []Comparison{LE},
+		ConstraintCoeff: NewSparseMatrixFromDense([][]float64{{1, 2}}),
+		Rhs:             []float64{10},
+		LowerBounds:     []float64{2, 0},
+		UpperBounds:     []float64{5, 4},
+	}
+
+	lp.ShiftLowerBounds()
+
+	if lp.Rhs[0] != 8 {
+		t.Errorf("Expected Rhs to be 8, but got %v", lp.Rhs[0])
+	}
+
+	if lp.ObjConstant != 6 {
+		t.Errorf("Expected ObjConstant to be 6, but got %v", lp.ObjConstant)
+	}
+
+	if lp.LowerBounds[0] != 0 || lp.UpperBounds[0] != 3 || lp.UpperBounds[1] != 4 {
+		t.Errorf("Expected bounds to be shifted to [0, 3] and [0, 4], but got %v and %v", lp.LowerBounds, lp.UpperBounds)
+	}
+
+	if lp.VariableOffsets[0] != 2 || lp.VariableOffsets[1] != 0 {
+		t.Errorf("Expected VariableOffsets to be [2, 0], but got %v", lp.VariableOffsets)
+	}
+}
+
+func TestSplitFreeVariables(t *testing.T) {
+	lp := &LinearProgram{
+		NbConstraints:   1,
+		NbVariables:     3,
+		VariableNames:   []string{"x", "y", "z"},
+		Objective:       MAXIMIZE,
+		ObjCoeff:        []float64{1, 2, 3},
+		Comparisons:     []Comparison{LE},
+		ConstraintCoeff: NewSparseMatrixFromDense([][]float64{{4, 5, 6}}),
+		Rhs:             []float64{10},
+		Signs:           []Sign{Free, NonPositive, NonNegative},
+	}
+
+	lp.SplitFreeVariables()
+
+	if lp.NbVariables != 4 || len(lp.SplitVariables) != 1 || lp.SplitVariables[0] != 0 {
+		t.Fatalf("Expected x to be split into a new column, but got NbVariables %d and SplitVariables %v", lp.NbVariables, lp.SplitVariables)
+	}
+
+	expectedCoeff := []float64{4, -5, 6, -4}
+	for j, coeff := range expectedCoeff {
+		if lp.ConstraintCoeff.At(0, j) != coeff {
+			t.Errorf("Expected ConstraintCoeff to be %v, but got %v", expectedCoeff, lp.ConstraintCoeff.Dense()[0])
+			break
+		}
+	}
+
+	if !lp.MirroredVariables[1] || lp.ObjCoeff[1] != -2 {
+		t.Errorf("Expected y to be mirrored, but got MirroredVariables %v and ObjCoeff %v", lp.MirroredVariables, lp.ObjCoeff)
+	}
+
+	for j := 0; j < lp.NbVariables; j++ {
+		if lower, _ := lp.Bounds(j); lower != 0 {
+			t.Errorf("Expected all lower bounds to be 0, but got %v", lp.LowerBounds)
+			break
+		}
+	}
+
+	// x = 1 - 3, y = -2, z = 5
+	values := lp.OriginalValues([]float64{1, 2, 5, 3})
+	if values[0] != -2 || values[1] != -2 || values[2] != 5 || len(values) != 3 {
+		t.Errorf("Expected original values to be [-2, -2, 5], but got %v", values)
+	}
+}
+
+func TestSplitFreeVariables_SparseRows(t *testing.T) {
+	// Two free variables and a mirrored one in rows that skip some of them: the negated columns come after the
+	// original ones in every row, in the order of their variables
+	lp := &LinearProgram{
+		NbConstraints: 2,
+		NbVariables:   4,
+		Objective:     MAXIMIZE,
+		ObjCoeff:      []float64{1, 1, 1, 1},
+		Comparisons:   []Comparison{LE, LE},
+		ConstraintCoeff: NewSparseMatrixFromDense([][]float64{
+			{1, 0, 2, 3},
+			{0, 4, 5, 0},
+		}),
+		Rhs:   []float64{10, 10},
+		Signs: []Sign{Free, NonPositive, NonNegative, Free},
+	}
+
+	lp.SplitFreeVariables()
+
+	expected := [][]float64{
+		{1, 0, 2, 3, -1, -3},
+		{0, -4, 5, 0, 0, 0},
+	}
+	if !reflect.DeepEqual(lp.ConstraintCoeff.Dense(), expected) {
+		t.Errorf("Expected ConstraintCoeff to be %v, but got %v", expected, lp.ConstraintCoeff.Dense())
+	}
+	for i, row := range lp.ConstraintCoeff.Rows {
+		if !sort.IntsAreSorted(row.Indices) {
+			t.Errorf("Expected the indices of row %d to be sorted, but got %v", i, row.Indices)
+		}
+	}
+}
+
+func TestRowOrigins(t *testing.T) {
+	lp := &LinearProgram{
+		NbConstraints:   3,
+		NbVariables:     2,
+		Objective:       MINIMIZE,
+		ObjCoeff:        []float64{1, 2},
+		Comparisons:     []Comparison{BE, LE, EQ},
+		ConstraintCoeff: NewSparseMatrixFromDense([][]float64{{1, 1}, {1, -1}, {2, 1}}),
+		Rhs:             []float64{3, -4, 5},
+		LowerBounds:     []float64{1, 0},
+	}
+
+	lp.ToCanonicalForm()
+
+	if lp.NbConstraints != 4 || lp.NbOriginalConstraints() != 3 {
+		t.Fatalf("Expected 4 rows from 3 constraints, but got %d rows from %d", lp.NbConstraints, lp.NbOriginalConstraints())
+	}
+
+	rows, signs := lp.OriginalRows(2)
+	if len(rows) != 2 || signs[0] != 1 || signs[1] != -1 {
+		t.Errorf("Expected the equality to become two rows of opposite signs, but got rows %v with signs %v", rows, signs)
+	}
+	// The >= row is negated by ConvertToLeConstraints, the row with a negative RHS twice
+	if _, signs := lp.OriginalRows(0); signs[0] != -1 {
+		t.Errorf("Expected the first row to be negated, but got sign %v", signs[0])
+	}
+	if _, signs := lp.OriginalRows(1); signs[0] != 1 {
+		t.Errorf("Expected the second row to keep its sign, but got sign %v", signs[0])
+	}
+
+	for i, rhs := range []float64{3, -4, 5} {
+		if lp.OriginalRhs(i) != rhs {
+			t.Errorf("Expected the original RHS of constraint %d to be %v, but got %v", i, rhs, lp.OriginalRhs(i))
+		}
+	}
+	if !lp.NegatedObjective || lp.OriginalObjCoeff(1) != 2 {
+		t.Errorf("Expected the objective to be negated and mapped back, but got %v", lp.OriginalObjCoeff(1))
 	}
 }
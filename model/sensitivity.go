@@ -0,0 +1,44 @@
+package model
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// Range is an interval of values. Its ends are infinite when the interval is unbounded.
+type Range struct {
+	Lower float64
+	Upper float64
+}
+
+// MarshalJSON writes the range as {"lower": ..., "upper": ...}, with null for an infinite end.
+func (r Range) MarshalJSON() ([]byte, error) {
+	end := func(v float64) *float64 {
+		if math.IsInf(v, 0) {
+			return nil
+		}
+		return &v
+	}
+	return json.Marshal(struct {
+		Lower *float64 `json:"lower"`
+		Upper *float64 `json:"upper"`
+	}{end(r.Lower), end(r.Upper)})
+}
+
+// Sensitivity holds the ranging of an optimal solution.
+type Sensitivity struct {
+	ObjCoeff []Range // Values of each objective coefficient for which the optimal basis stays optimal
+	Rhs      []Range // Values of each right-hand side for which the optimal basis stays feasible
+}
+
+// sensitivityJSON returns the ranges keyed by variable name for the objective and by constraint index for the RHS.
+func (lp *LinearProgram) sensitivityJSON() interface{} {
+	objective := make(map[string]Range)
+	for j, r := range lp.Sensitivity.ObjCoeff {
+		objective[lp.VariableName(j)] = r
+	}
+	return struct {
+		Objective map[string]Range `json:"objective"`
+		Rhs       []Range          `json:"rhs"`
+	}{objective, lp.Sensitivity.Rhs}
+}
@@ -0,0 +1,183 @@
+package model
+
+import "sort"
+
+// SparseVector holds the non-zero entries of a vector, sorted by index.
+type SparseVector struct {
+	Indices []int
+	Values  []float64
+}
+
+// NewSparseVector returns the sparse form of a dense vector.
+func NewSparseVector(dense []float64) SparseVector {
+	var v SparseVector
+	for j, value := range dense {
+		if value != 0 {
+			v.Indices = append(v.Indices, j)
+			v.Values = append(v.Values, value)
+		}
+	}
+	return v
+}
+
+// At returns the entry at the given index.
+func (v SparseVector) At(index int) float64 {
+	k := sort.SearchInts(v.Indices, index)
+	if k < len(v.Indices) && v.Indices[k] == index {
+		return v.Values[k]
+	}
+	return 0
+}
+
+// Set changes the entry at the given index, a zero value removes it.
+// Setting an index past the last entry is done in constant time.
+func (v *SparseVector) Set(index int, value float64) {
+	n := len(v.Indices)
+	if n == 0 || index > v.Indices[n-1] {
+		if value != 0 {
+			v.Indices = append(v.Indices, index)
+			v.Values = append(v.Values, value)
+		}
+		return
+	}
+
+	k := sort.SearchInts(v.Indices, index)
+	switch {
+	case k < n && v.Indices[k] == index && value != 0:
+		v.Values[k] = value
+	case k < n && v.Indices[k] == index:
+		v.Indices = append(v.Indices[:k], v.Indices[k+1:]...)
+		v.Values = append(v.Values[:k], v.Values[k+1:]...)
+	case value != 0:
+		v.Indices = append(v.Indices[:k], append([]int{index}, v.Indices[k:]...)...)
+		v.Values = append(v.Values[:k], append([]float64{value}, v.Values[k:]...)...)
+	}
+}
+
+// Scale returns a copy of the vector multiplied by a scalar.
+func (v SparseVector) Scale(scalar float64) SparseVector {
+	scaled := v.Clone()
+	for k := range scaled.Values {
+		scaled.Values[k] *= scalar
+	}
+	return scaled
+}
+
+// Dot returns the dot product with a dense vector.
+func (v SparseVector) Dot(dense []float64) float64 {
+	sum := 0.0
+	for k, j := range v.Indices {
+		sum += v.Values[k] * dense[j]
+	}
+	return sum
+}
+
+// Dense returns the vector as a dense slice of the given length.
+func (v SparseVector) Dense(length int) []float64 {
+	dense := make([]float64, length)
+	for k, j := range v.Indices {
+		dense[j] = v.Values[k]
+	}
+	return dense
+}
+
+// Clone returns a deep copy of the vector.
+func (v SparseVector) Clone() SparseVector {
+	return SparseVector{
+		Indices: append([]int(nil), v.Indices...),
+		Values:  append([]float64(nil), v.Values...),
+	}
+}
+
+// SparseMatrix stores a matrix in compressed sparse row form: every row only keeps its non-zero
+// entries. Rows and columns can be appended without touching the rest of the matrix.
+type SparseMatrix struct {
+	Rows   []SparseVector
+	NbCols int
+}
+
+// NewSparseMatrix returns an empty matrix with the given dimensions.
+func NewSparseMatrix(nbRows, nbCols int) *SparseMatrix {
+	return &SparseMatrix{Rows: make([]SparseVector, nbRows), NbCols: nbCols}
+}
+
+// NewSparseMatrixFromDense returns the sparse form of a dense matrix.
+func NewSparseMatrixFromDense(dense [][]float64) *SparseMatrix {
+	m := &SparseMatrix{Rows: make([]SparseVector, len(dense))}
+	for i, row := range dense {
+		m.Rows[i] = NewSparseVector(row)
+		if len(row) > m.NbCols {
+			m.NbCols = len(row)
+		}
+	}
+	return m
+}
+
+// NbRows returns the number of rows.
+func (m *SparseMatrix) NbRows() int {
+	return len(m.Rows)
+}
+
+// At returns the entry in row i and column j.
+func (m *SparseMatrix) At(i, j int) float64 {
+	return m.Rows[i].At(j)
+}
+
+// Set changes the entry in row i and column j.
+func (m *SparseMatrix) Set(i, j int, value float64) {
+	m.Rows[i].Set(j, value)
+}
+
+// AddRow appends a row given in dense form and returns its index.
+func (m *SparseMatrix) AddRow(dense []float64) int {
+	m.Rows = append(m.Rows, NewSparseVector(dense))
+	if len(dense) > m.NbCols {
+		m.NbCols = len(dense)
+	}
+	return len(m.Rows) - 1
+}
+
+// AddColumn appends an empty column and returns its index.
+func (m *SparseMatrix) AddColumn() int {
+	m.NbCols++
+	return m.NbCols - 1
+}
+
+// NonZeros returns the number of stored entries.
+func (m *SparseMatrix) NonZeros() int {
+	count := 0
+	for _, row := range m.Rows {
+		count += len(row.Indices)
+	}
+	return count
+}
+
+// Transpose returns the transposed matrix. Its rows are the columns of m, i.e. m in compressed sparse column form.
+func (m *SparseMatrix) Transpose() *SparseMatrix {
+	t := NewSparseMatrix(m.NbCols, len(m.Rows))
+	for i, row := range m.Rows {
+		for k, j := range row.Indices {
+			t.Rows[j].Indices = append(t.Rows[j].Indices, i)
+			t.Rows[j].Values = append(t.Rows[j].Values, row.Values[k])
+		}
+	}
+	return t
+}
+
+// Dense returns the matrix as dense rows.
+func (m *SparseMatrix) Dense() [][]float64 {
+	dense := make([][]float64, len(m.Rows))
+	for i, row := range m.Rows {
+		dense[i] = row.Dense(m.NbCols)
+	}
+	return dense
+}
+
+// Clone returns a deep copy of the matrix.
+func (m *SparseMatrix) Clone() *SparseMatrix {
+	c := &SparseMatrix{Rows: make([]SparseVector, len(m.Rows)), NbCols: m.NbCols}
+	for i, row := range m.Rows {
+		c.Rows[i] = row.Clone()
+	}
+	return c
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSparseMatrix(t *testing.T) {
+	m := NewSparseMatrixFromDense([][]float64{
+		{1, 0, 2},
+		{0, 0, 3},
+	})
+	if m.NbRows() != 2 || m.NbCols != 3 || m.NonZeros() != 3 {
+		t.Fatalf("Expected a 2x3 matrix with 3 non-zeros, got %dx%d with %d", m.NbRows(), m.NbCols, m.NonZeros())
+	}
+
+	m.Set(1, 0, 4) // insert before an existing entry
+	m.Set(0, 2, 0) // remove an entry
+	m.Set(0, 0, 5) // overwrite an entry
+	col := m.AddColumn()
+	m.Set(1, col, -1)
+
+	expected := [][]float64{
+		{5, 0, 0, 0},
+		{4, 0, 3, -1},
+	}
+	if !reflect.DeepEqual(m.Dense(), expected) {
+		t.Errorf("Expected %v, got %v", expected, m.Dense())
+	}
+	if m.At(1, 2) != 3 || m.At(0, 1) != 0 {
+		t.Errorf("Expected At(1, 2) = 3 and At(0, 1) = 0, got %v and %v", m.At(1, 2), m.At(0, 1))
+	}
+
+	transposed := [][]float64{
+		{5, 4},
+		{0, 0},
+		{0, 3},
+		{0, -1},
+	}
+	if !reflect.DeepEqual(m.Transpose().Dense(), transposed) {
+		t.Errorf("Expected transpose %v, got %v", transposed, m.Transpose().Dense())
+	}
+
+	if dot := m.Rows[1].Dot([]float64{1, 1, 1, 1}); dot != 6 {
+		t.Errorf("Expected dot product 6, got %v", dot)
+	}
+	if scaled := m.Rows[1].Scale(-1); scaled.At(0) != -4 || m.At(1, 0) != 4 {
+		t.Errorf("Expected Scale to return a negated copy, got %v", scaled)
+	}
+
+	if row := m.AddRow([]float64{0, 7, 0, 0}); row != 2 || m.NbRows() != 3 || m.At(2, 1) != 7 || m.NonZeros() != 5 {
+		t.Errorf("Expected AddRow to append row 2 with one non-zero, got row %d of %v", row, m.Dense())
+	}
+}
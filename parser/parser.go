@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
@@ -24,6 +25,9 @@ type JSONLinearProgram struct {
 	NumberOfConstraints int               `json:"numberOfConstraints"`
 	ObjectiveFunction   ObjectiveFunction `json:"objectiveFunction"`
 	Constraints         []string          `json:"constraints"`
+	Bounds              []string          `json:"bounds,omitempty"`
+	Signs               map[string]string `json:"signs,omitempty"`
+	Types               map[string]string `json:"types,omitempty"`
 }
 
 // ObjectiveFunction is the structure for parsing the objective function from JSON.
@@ -58,6 +62,21 @@ func Parse(jsonData string) (*model.LinearProgram, error) {
 		return nil, err
 	}
 
+	err = parseSigns(lp, jsonLP, varMap)
+	if err != nil {
+		return nil, err
+	}
+
+	err = parseBounds(lp, jsonLP, varMap)
+	if err != nil {
+		return nil, err
+	}
+
+	err = parseTypes(lp, jsonLP, varMap)
+	if err != nil {
+		return nil, err
+	}
+
 	return lp, nil
 }
 
@@ -82,17 +101,19 @@ func parseObjectiveFunction(lp *model.LinearProgram, jsonLP *JSONLinearProgram,
 	}
 
 	lp.ObjCoeff = make([]float64, lp.NbVariables)
-	parseEquation(jsonLP.ObjectiveFunction.Equation, lp.ObjCoeff, varMap)
+	parseEquation(jsonLP.ObjectiveFunction.Equation, func(idx int, coeff float64) {
+		lp.ObjCoeff[idx] = coeff
+	}, varMap)
 	return nil
 }
 
 func parseConstraints(lp *model.LinearProgram, jsonLP *JSONLinearProgram, varMap map[string]int) error {
-	lp.ConstraintCoeff = make([][]float64, lp.NbConstraints)
+	lp.ConstraintCoeff = model.NewSparseMatrix(lp.NbConstraints, lp.NbVariables)
 	lp.Rhs = make([]float64, lp.NbConstraints)
 	lp.Comparisons = make([]model.Comparison, lp.NbConstraints)
+	lp.ConstraintTexts = make([]string, lp.NbConstraints)
 
 	for i, constr := range jsonLP.Constraints {
-		lp.ConstraintCoeff[i] = make([]float64, lp.NbVariables)
 		parts := compRegex.Split(constr, -1)
 		compStr := compRegex.FindString(constr)
 
@@ -101,13 +122,16 @@ func parseConstraints(lp *model.LinearProgram, jsonLP *JSONLinearProgram, varMap
 			return err
 		}
 
-		parseEquation(parts[0], lp.ConstraintCoeff[i], varMap)
+		parseEquation(parts[0], func(idx int, coeff float64) {
+			lp.ConstraintCoeff.Set(i, idx, coeff)
+		}, varMap)
 
 		bVal, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
 		if err != nil {
 			return err
 		}
 		lp.Rhs[i] = bVal
+		lp.ConstraintTexts[i] = constr
 	}
 	return nil
 }
@@ -130,7 +154,148 @@ func setComparison(lp *model.LinearProgram, index int, compStr string) error {
 	return nil
 }
 
-func parseEquation(equation string, coeffs []float64, varMap map[string]int) {
+// parseBounds reads variable bounds such as "x <= 10", "y >= -5", "2 <= z <= 8" or "w = 4".
+func parseBounds(lp *model.LinearProgram, jsonLP *JSONLinearProgram, varMap map[string]int) error {
+	if len(jsonLP.Bounds) == 0 {
+		return nil
+	}
+
+	// Start from the bounds implied by the signs of the variables
+	lowerBounds := make([]float64, lp.NbVariables)
+	upperBounds := make([]float64, lp.NbVariables)
+	for j := range upperBounds {
+		lowerBounds[j], upperBounds[j] = lp.Bounds(j)
+	}
+	lp.LowerBounds = lowerBounds
+	lp.UpperBounds = upperBounds
+
+	for _, bound := range jsonLP.Bounds {
+		parts := compRegex.Split(bound, -1)
+		compStrs := compRegex.FindAllString(bound, -1)
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+
+		var err error
+		switch len(compStrs) {
+		case 1:
+			if idx, ok := varMap[parts[0]]; ok {
+				err = setBound(lp, idx, compStrs[0], parts[1])
+			} else if idx, ok := varMap[parts[1]]; ok {
+				err = setBound(lp, idx, reverseComparison(compStrs[0]), parts[0])
+			} else {
+				err = fmt.Errorf("invalid bound, unknown variable: %s", bound)
+			}
+		case 2:
+			idx, ok := varMap[parts[1]]
+			if !ok {
+				return fmt.Errorf("invalid bound, unknown variable: %s", bound)
+			}
+			err = setBound(lp, idx, reverseComparison(compStrs[0]), parts[0])
+			if err == nil {
+				err = setBound(lp, idx, compStrs[1], parts[2])
+			}
+		default:
+			err = fmt.Errorf("invalid bound: %s", bound)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseSigns reads the sign restrictions of the variables: "nonnegative", "nonpositive" or "free".
+func parseSigns(lp *model.LinearProgram, jsonLP *JSONLinearProgram, varMap map[string]int) error {
+	if len(jsonLP.Signs) == 0 {
+		return nil
+	}
+
+	lp.Signs = make([]model.Sign, lp.NbVariables)
+	for name, signStr := range jsonLP.Signs {
+		idx, ok := varMap[name]
+		if !ok {
+			return fmt.Errorf("invalid sign, unknown variable: %s", name)
+		}
+		switch strings.ToLower(signStr) {
+		case "nonnegative":
+			lp.Signs[idx] = model.NonNegative
+		case "nonpositive":
+			lp.Signs[idx] = model.NonPositive
+		case "free":
+			lp.Signs[idx] = model.Free
+		default:
+			return fmt.Errorf("invalid sign for variable %s: %s", name, signStr)
+		}
+	}
+	return nil
+}
+
+// parseTypes reads the types of the variables: "continuous", "integer" or "binary".
+func parseTypes(lp *model.LinearProgram, jsonLP *JSONLinearProgram, varMap map[string]int) error {
+	if len(jsonLP.Types) == 0 {
+		return nil
+	}
+
+	lp.Types = make([]model.VariableType, lp.NbVariables)
+	for name, typeStr := range jsonLP.Types {
+		idx, ok := varMap[name]
+		if !ok {
+			return fmt.Errorf("invalid type, unknown variable: %s", name)
+		}
+		switch strings.ToLower(typeStr) {
+		case "continuous":
+			lp.Types[idx] = model.Continuous
+		case "integer":
+			lp.Types[idx] = model.Integer
+		case "binary":
+			lp.Types[idx] = model.Binary
+		default:
+			return fmt.Errorf("invalid type for variable %s: %s", name, typeStr)
+		}
+	}
+	return nil
+}
+
+// setBound applies "variable compStr value" to the bounds of the variable at the given index.
+func setBound(lp *model.LinearProgram, index int, compStr string, valueStr string) error {
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return err
+	}
+
+	switch compStr {
+	case "<", "<=":
+		lp.UpperBounds[index] = value
+	case ">", ">=":
+		lp.LowerBounds[index] = value
+	case "=":
+		lp.LowerBounds[index] = value
+		lp.UpperBounds[index] = value
+	default:
+		return fmt.Errorf("invalid comparison operator: %s", compStr)
+	}
+	return nil
+}
+
+// reverseComparison returns the operator obtained by swapping both sides of a comparison.
+func reverseComparison(compStr string) string {
+	switch compStr {
+	case "<":
+		return ">"
+	case "<=":
+		return ">="
+	case ">":
+		return "<"
+	case ">=":
+		return "<="
+	default:
+		return compStr
+	}
+}
+
+// parseEquation passes the coefficient of every known variable of the equation to set.
+func parseEquation(equation string, set func(idx int, coeff float64), varMap map[string]int) {
 	matches := coeffRegex.FindAllStringSubmatch(equation, -1)
 
 	for _, match := range matches {
@@ -146,7 +311,7 @@ func parseEquation(equation string, coeffs []float64, varMap map[string]int) {
 
 		varName := match[3]
 		if idx, ok := varMap[varName]; ok {
-			coeffs[idx] = sign * coeff
+			set(idx, sign*coeff)
 		}
 	}
 }
@@ -171,6 +336,10 @@ func ConvertLPToJSON(lp *model.LinearProgram) (string, error) {
 		NumberOfConstraints: lp.NbConstraints,
 	}
 
+	// Columns are ordered as variables, negative parts of split variables, then slack variables
+	allVarNames := append([]string{}, lp.VariableNames...)
+	allVarNames = append(allVarNames, lp.SplitVariablesNames...)
+
 	// Convert objective function
 	objObjective := "maximize"
 	if lp.Objective == model.MINIMIZE {
@@ -178,7 +347,7 @@ func ConvertLPToJSON(lp *model.LinearProgram) (string, error) {
 	}
 	jsonLP.ObjectiveFunction = ObjectiveFunction{
 		Objective: objObjective,
-		Equation:  equationToString(lp.ObjCoeff, lp.VariableNames, lp.SlackVariablesNames),
+		Equation:  equationToString(model.NewSparseVector(lp.ObjCoeff), allVarNames, lp.SlackVariablesNames),
 	}
 
 	// Convert constraints
@@ -188,9 +357,14 @@ func ConvertLPToJSON(lp *model.LinearProgram) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		jsonLP.Constraints[i] = equationToString(lp.ConstraintCoeff[i], lp.VariableNames, lp.SlackVariablesNames) + " " + compStr + " " + strconv.FormatFloat(lp.Rhs[i], 'f', -1, 64)
+		jsonLP.Constraints[i] = equationToString(lp.ConstraintCoeff.Rows[i], allVarNames, lp.SlackVariablesNames) + " " + compStr + " " + strconv.FormatFloat(lp.Rhs[i], 'f', -1, 64)
 	}
 
+	// Convert signs and bounds
+	jsonLP.Signs = signsToStrings(lp)
+	jsonLP.Bounds = boundsToStrings(lp)
+	jsonLP.Types = typesToStrings(lp)
+
 	var buf bytes.Buffer
 	enc := json.NewEncoder(&buf)
 	enc.SetEscapeHTML(false)
@@ -203,10 +377,11 @@ func ConvertLPToJSON(lp *model.LinearProgram) (string, error) {
 	return buf.String(), nil
 }
 
-func equationToString(coeffs []float64, varNames []string, slackVariablesNames []string) string {
+func equationToString(coeffs model.SparseVector, varNames []string, slackVariablesNames []string) string {
 	var parts []string
 	allVarNames := append(varNames, slackVariablesNames...)
-	for i, coeff := range coeffs {
+	for k, i := range coeffs.Indices {
+		coeff := coeffs.Values[k]
 		if coeff == 0 {
 			continue
 		}
@@ -223,6 +398,69 @@ func equationToString(coeffs []float64, varNames []string, slackVariablesNames [
 	return strings.Join(parts, " ")
 }
 
+// signsToStrings returns the sign restrictions of the variables that are not non-negative.
+func signsToStrings(lp *model.LinearProgram) map[string]string {
+	signs := make(map[string]string)
+	for j, sign := range lp.Signs {
+		switch sign {
+		case model.NonPositive:
+			signs[lp.VariableName(j)] = "nonpositive"
+		case model.Free:
+			signs[lp.VariableName(j)] = "free"
+		}
+	}
+	if len(signs) == 0 {
+		return nil
+	}
+	return signs
+}
+
+// typesToStrings returns the types of the integer and binary variables.
+func typesToStrings(lp *model.LinearProgram) map[string]string {
+	types := make(map[string]string)
+	for j, varType := range lp.Types {
+		switch varType {
+		case model.Integer:
+			types[lp.VariableName(j)] = "integer"
+		case model.Binary:
+			types[lp.VariableName(j)] = "binary"
+		}
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	return types
+}
+
+// boundsToStrings formats the bounds of the variables that differ from the bounds implied by their sign
+// (and by their type for binary variables).
+func boundsToStrings(lp *model.LinearProgram) []string {
+	var bounds []string
+	for j, name := range lp.VariableNames {
+		lower, upper := lp.Bounds(j)
+		signLower, signUpper := model.SignBounds(model.NonNegative)
+		if j < len(lp.Signs) {
+			signLower, signUpper = model.SignBounds(lp.Signs[j])
+		}
+		if j < len(lp.Types) && lp.Types[j] == model.Binary {
+			signLower, signUpper = math.Max(signLower, 0), math.Min(signUpper, 1)
+		}
+		lowerStr := strconv.FormatFloat(lower, 'f', -1, 64)
+		upperStr := strconv.FormatFloat(upper, 'f', -1, 64)
+		switch {
+		case lower == upper:
+			bounds = append(bounds, name+" = "+lowerStr)
+		case lower != signLower && upper != signUpper:
+			bounds = append(bounds, lowerStr+" <= "+name+" <= "+upperStr)
+		case lower != signLower:
+			bounds = append(bounds, name+" >= "+lowerStr)
+		case upper != signUpper:
+			bounds = append(bounds, name+" <= "+upperStr)
+		}
+	}
+	return bounds
+}
+
 func comparisonToString(comp model.Comparison) (string, error) {
 	switch comp {
 	case model.EQ:
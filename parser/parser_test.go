@@ -2,6 +2,8 @@ package parser
 
 import (
 	"io/ioutil"
+	"math"
+	"strings"
 	"testing"
 
 	"github.com/Chemberlein/LinearProgrammingTools/model"
@@ -41,8 +43,8 @@ func TestParse(t *testing.T) {
 		{0, 4, 3},
 		{12, 4, 3},
 	}
-	if !equalFloat64Matrices(lp.ConstraintCoeff, expectedConstraintCoeff) {
-		t.Errorf("Expected ConstraintCoeff to be %v, got %v", expectedConstraintCoeff, lp.ConstraintCoeff)
+	if !equalFloat64Matrices(lp.ConstraintCoeff.Dense(), expectedConstraintCoeff) {
+		t.Errorf("Expected ConstraintCoeff to be %v, got %v", expectedConstraintCoeff, lp.ConstraintCoeff.Dense())
 	}
 
 	expectedRhs := []float64{200, 430, 430}
@@ -54,6 +56,11 @@ func TestParse(t *testing.T) {
 	if !equalComparisonSlices(lp.Comparisons, expectedComparisons) {
 		t.Errorf("Expected Comparisons to be %v, got %v", expectedComparisons, lp.Comparisons)
 	}
+
+	expectedTexts := []string{"5*y < 200", "4*y + 3*z < 430", "4*y + 3*z + 12*x < 430"}
+	if strings.Join(lp.ConstraintTexts, "|") != strings.Join(expectedTexts, "|") {
+		t.Errorf("Expected ConstraintTexts to be %q, got %q", expectedTexts, lp.ConstraintTexts)
+	}
 }
 
 func TestParse2(t *testing.T) {
@@ -90,8 +97,8 @@ func TestParse2(t *testing.T) {
 		{0, 2},
 		{3, 2},
 	}
-	if !equalFloat64Matrices(lp.ConstraintCoeff, expectedConstraintCoeff) {
-		t.Errorf("Expected ConstraintCoeff to be %v, got %v", expectedConstraintCoeff, lp.ConstraintCoeff)
+	if !equalFloat64Matrices(lp.ConstraintCoeff.Dense(), expectedConstraintCoeff) {
+		t.Errorf("Expected ConstraintCoeff to be %v, got %v", expectedConstraintCoeff, lp.ConstraintCoeff.Dense())
 	}
 
 	expectedRhs := []float64{4, 12, 18}
@@ -138,8 +145,8 @@ func TestParse3(t *testing.T) {
 		{1, 1},
 		{2, 1},
 	}
-	if !equalFloat64Matrices(lp.ConstraintCoeff, expectedConstraintCoeff) {
-		t.Errorf("Expected ConstraintCoeff to be %v, got %v", expectedConstraintCoeff, lp.ConstraintCoeff)
+	if !equalFloat64Matrices(lp.ConstraintCoeff.Dense(), expectedConstraintCoeff) {
+		t.Errorf("Expected ConstraintCoeff to be %v, got %v", expectedConstraintCoeff, lp.ConstraintCoeff.Dense())
 	}
 
 	expectedRhs := []float64{10, 20}
@@ -153,6 +160,147 @@ func TestParse3(t *testing.T) {
 	}
 }
 
+func TestParseBounds(t *testing.T) {
+	jsonData, err := ioutil.ReadFile("tests/example5.json")
+	if err != nil {
+		t.Fatalf("Failed to read example5.json: %v", err)
+	}
+
+	lp, err := Parse(string(jsonData))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	expectedLowerBounds := []float64{0, 1, 2}
+	if !equalFloat64Slices(lp.LowerBounds, expectedLowerBounds) {
+		t.Errorf("Expected LowerBounds to be %v, got %v", expectedLowerBounds, lp.LowerBounds)
+	}
+
+	expectedUpperBounds := []float64{3, 6, math.Inf(1)}
+	if !equalFloat64Slices(lp.UpperBounds, expectedUpperBounds) {
+		t.Errorf("Expected UpperBounds to be %v, got %v", expectedUpperBounds, lp.UpperBounds)
+	}
+
+	jsonString, err := ConvertLPToJSON(lp)
+	if err != nil {
+		t.Fatalf("ConvertLPToJSON() error = %v", err)
+	}
+	if !strings.Contains(jsonString, `"1 <= y <= 6"`) || !strings.Contains(jsonString, `"z >= 2"`) {
+		t.Errorf("Expected the bounds to be written back, got %s", jsonString)
+	}
+}
+
+func TestParseBounds_UnknownVariable(t *testing.T) {
+	jsonData := `{
+		"numberOfVariables": 1,
+		"numberOfConstraints": 1,
+		"objectiveFunction": {"objective": "maximize", "equasion": "x"},
+		"constraints": ["x <= 4"],
+		"bounds": ["y <= 2"]
+	}`
+
+	_, err := Parse(jsonData)
+	if err == nil {
+		t.Errorf("Expected an error for a bound on an unknown variable, got nil")
+	}
+}
+
+func TestParseSigns(t *testing.T) {
+	jsonData := `{
+		"numberOfVariables": 3,
+		"numberOfConstraints": 1,
+		"objectiveFunction": {"objective": "minimize", "equasion": "x + y + z"},
+		"constraints": ["x + y + z >= -10"],
+		"signs": {"x": "free", "y": "nonpositive"},
+		"bounds": ["x <= 4"]
+	}`
+
+	lp, err := Parse(jsonData)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	expectedSigns := []model.Sign{model.Free, model.NonPositive, model.NonNegative}
+	for j, sign := range expectedSigns {
+		if lp.Signs[j] != sign {
+			t.Errorf("Expected Signs to be %v, got %v", expectedSigns, lp.Signs)
+			break
+		}
+	}
+
+	expectedLowerBounds := []float64{math.Inf(-1), math.Inf(-1), 0}
+	if !equalFloat64Slices(lp.LowerBounds, expectedLowerBounds) {
+		t.Errorf("Expected LowerBounds to be %v, got %v", expectedLowerBounds, lp.LowerBounds)
+	}
+
+	expectedUpperBounds := []float64{4, 0, math.Inf(1)}
+	if !equalFloat64Slices(lp.UpperBounds, expectedUpperBounds) {
+		t.Errorf("Expected UpperBounds to be %v, got %v", expectedUpperBounds, lp.UpperBounds)
+	}
+
+	jsonString, err := ConvertLPToJSON(lp)
+	if err != nil {
+		t.Fatalf("ConvertLPToJSON() error = %v", err)
+	}
+	if !strings.Contains(jsonString, `"x": "free"`) || !strings.Contains(jsonString, `"x <= 4"`) || strings.Contains(jsonString, `"y <= 0"`) {
+		t.Errorf("Expected the signs and bounds to be written back, got %s", jsonString)
+	}
+}
+
+func TestParseSigns_Invalid(t *testing.T) {
+	jsonData := `{
+		"numberOfVariables": 1,
+		"numberOfConstraints": 1,
+		"objectiveFunction": {"objective": "maximize", "equasion": "x"},
+		"constraints": ["x <= 4"],
+		"signs": {"x": "positive"}
+	}`
+
+	_, err := Parse(jsonData)
+	if err == nil {
+		t.Errorf("Expected an error for an invalid sign, got nil")
+	}
+}
+
+func TestParseTypes(t *testing.T) {
+	jsonData := `{
+		"numberOfVariables": 3,
+		"numberOfConstraints": 1,
+		"objectiveFunction": {"objective": "maximize", "equasion": "x + y + z"},
+		"constraints": ["x + y + z <= 10"],
+		"types": {"x": "integer", "y": "binary"}
+	}`
+
+	lp, err := Parse(jsonData)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	expectedTypes := []model.VariableType{model.Integer, model.Binary, model.Continuous}
+	for j, varType := range expectedTypes {
+		if lp.Types[j] != varType {
+			t.Errorf("Expected Types to be %v, got %v", expectedTypes, lp.Types)
+			break
+		}
+	}
+	if lower, upper := lp.Bounds(1); lower != 0 || upper != 1 {
+		t.Errorf("Expected the binary variable to be within [0, 1], got [%v, %v]", lower, upper)
+	}
+
+	jsonString, err := ConvertLPToJSON(lp)
+	if err != nil {
+		t.Fatalf("ConvertLPToJSON() error = %v", err)
+	}
+	if !strings.Contains(jsonString, `"x": "integer"`) || !strings.Contains(jsonString, `"y": "binary"`) || strings.Contains(jsonString, `"bounds"`) {
+		t.Errorf("Expected the types to be written back without bounds, got %s", jsonString)
+	}
+
+	jsonData = strings.Replace(jsonData, `"binary"`, `"boolean"`, 1)
+	if _, err := Parse(jsonData); err == nil {
+		t.Errorf("Expected an error for an invalid type, got nil")
+	}
+}
+
 func equalFloat64Slices(a, b []float64) bool {
 	if len(a) != len(b) {
 		return false
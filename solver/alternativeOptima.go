@@ -0,0 +1,212 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// DefaultMaxOptima is the number of optimal solutions returned by EnumerateOptima when
+// OptimaOptions.MaxSolutions is not set.
+const DefaultMaxOptima = 100
+
+// OptimaOptions configures a call to EnumerateOptima.
+type OptimaOptions struct {
+	LP           SolveOptions // Options of the first solve, which always runs on the tableau
+	MaxSolutions int          // Maximum number of solutions to return, 0 means DefaultMaxOptima
+}
+
+// alternativeOptimum reports whether a nonbasic column with a zero reduced cost can enter the optimal tableau
+// with a positive step that changes the original variables, which reaches another optimal solution.
+func (table *SimplexTable) alternativeOptimum(lp *model.LinearProgram) bool {
+	for _, col := range table.zeroReducedCosts() {
+		step := table.enteringStep(col)
+		if step > 1e-9 && movesOriginal(lp, table.edgeDirection(col)) {
+			return true
+		}
+	}
+	return false
+}
+
+// zeroReducedCosts returns the nonbasic columns of the optimal tableau whose objective row entry is zero,
+// which can enter the basis without changing the objective value.
+func (table *SimplexTable) zeroReducedCosts() []int {
+	epsilon := 1e-9
+	objectiveRow := table.data[len(table.data)-1]
+
+	basic := make([]bool, len(objectiveRow)-1)
+	for _, col := range table.basicVariables {
+		basic[int(col)] = true
+	}
+	var columns []int
+	for j := range basic {
+		if !basic[j] && !table.isArtificial(j) && math.Abs(objectiveRow[j]) <= epsilon {
+			columns = append(columns, j)
+		}
+	}
+	return columns
+}
+
+// enteringStep returns how far the nonbasic column can increase before a basic variable or the column itself
+// reaches a bound, +Inf if nothing stops it.
+func (table *SimplexTable) enteringStep(col int) float64 {
+	step := table.upperBound(col)
+	if row := table.FindLeavingVariable(col); row != -1 {
+		ratio, _ := table.leavingRatio(row, col)
+		step = math.Min(step, ratio)
+	}
+	return step
+}
+
+// movesOriginal reports whether a direction over the columns of the slack form changes an original variable.
+// Moving both parts of a split free variable together, for example, leaves the variable where it is.
+func movesOriginal(lp *model.LinearProgram, direction []float64) bool {
+	for _, d := range lp.OriginalDirection(direction) {
+		if math.Abs(d) > 1e-9 {
+			return true
+		}
+	}
+	return false
+}
+
+// alternativeOptimum reports whether a nonbasic column with a zero reduced cost can move away from its bound
+// by a positive step that changes the original variables, which reaches another optimal solution.
+func (rs *RevisedSimplexSolver) alternativeOptimum(lp *model.LinearProgram) bool {
+	epsilon := 1e-9
+	y := rs.ExtractDuals()
+
+	for j := 0; j < rs.numCols; j++ {
+		if rs.basicRow[j] != -1 || math.Abs(rs.cost[j]-rs.columns[j].Dot(y)) > epsilon {
+			continue
+		}
+
+		direction := 1.0
+		if rs.atUpper[j] {
+			direction = -1.0
+		}
+		alpha := rs.Ftran(rs.column(j))
+		_, step, _ := rs.FindLeavingVariable(alpha, direction)
+		if step = math.Min(step, rs.upper[j]); step <= epsilon {
+			continue
+		}
+
+		edge := make([]float64, rs.numCols)
+		edge[j] = direction
+		for i, col := range rs.basis {
+			if col < rs.numCols {
+				edge[col] = -direction * alpha[i]
+			}
+		}
+		if movesOriginal(lp, edge) {
+			return true
+		}
+	}
+	return false
+}
+
+// clone returns a copy of the tableau that can be pivoted independently.
+func (table *SimplexTable) clone() *SimplexTable {
+	clone := &SimplexTable{
+		data:              make([][]float64, len(table.data)),
+		basicVariables:    append([]float64(nil), table.basicVariables...),
+		artificials:       append([]int(nil), table.artificials...),
+		upperBounds:       append([]float64(nil), table.upperBounds...),
+		atUpper:           append([]bool(nil), table.atUpper...),
+		phaseTwoObjective: append([]float64(nil), table.phaseTwoObjective...),
+	}
+	for i, row := range table.data {
+		clone.data[i] = append([]float64(nil), row...)
+	}
+	return clone
+}
+
+// basisKey identifies the basis of the tableau: its basic columns and the complemented nonbasic ones.
+func (table *SimplexTable) basisKey() string {
+	basic := make([]int, len(table.basicVariables))
+	for i, col := range table.basicVariables {
+		basic[i] = int(col)
+	}
+	sort.Ints(basic)
+
+	var sb strings.Builder
+	fmt.Fprint(&sb, basic)
+	for j, atUpper := range table.atUpper {
+		if atUpper {
+			fmt.Fprintf(&sb, " u%d", j)
+		}
+	}
+	return sb.String()
+}
+
+// EnumerateOptima returns the optimal basic solutions of the problem, each one as the values of the original
+// variables followed by the objective value, like ObjVar. It solves the problem on the tableau, then pivots on
+// the nonbasic columns with a zero reduced cost, which keep the objective value, and visits every optimal basis
+// reachable that way, up to MaxSolutions distinct solutions. The first solution is the one Solve returns.
+// Every point of the segments between the solutions is optimal as well; an optimal face that is unbounded
+// also contains the rays leaving these vertices, which are not enumerated. The problem is left unchanged.
+func EnumerateOptima(lp *model.LinearProgram, opts OptimaOptions) ([][]float64, error) {
+	maxSolutions := opts.MaxSolutions
+	if maxSolutions <= 0 {
+		maxSolutions = DefaultMaxOptima
+	}
+
+	relaxation := lp.Clone()
+	optimal, err := optimalTableau(relaxation, opts.LP)
+	if err != nil {
+		return nil, err
+	}
+
+	var solutions [][]float64
+	seen := make(map[string]bool)
+	visited := map[string]bool{optimal.basisKey(): true}
+	stack := []*SimplexTable{optimal}
+	for len(stack) > 0 && len(solutions) < maxSolutions {
+		table := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		solution := originalSolution(relaxation, table.ExtractSolution(relaxation), lp.Objective)
+		key := fmt.Sprint(roundedValues(solution))
+		if !seen[key] {
+			seen[key] = true
+			solutions = append(solutions, solution)
+		}
+
+		// Push in reverse order, so that the columns are explored from the first one
+		columns := table.zeroReducedCosts()
+		for k := len(columns) - 1; k >= 0; k-- {
+			col := columns[k]
+			step := table.enteringStep(col)
+			if math.IsInf(step, 1) {
+				continue // An unbounded edge of the optimal face
+			}
+
+			next := table.clone()
+			if row := next.FindLeavingVariable(col); row == -1 || next.upperBound(col) <= step {
+				next.complement(col)
+			} else {
+				next.pivot(row, col)
+			}
+			if key := next.basisKey(); !visited[key] {
+				visited[key] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	return solutions, nil
+}
+
+// roundedValues rounds the values to 9 decimals, so that the same vertex reached through different bases
+// compares equal.
+func roundedValues(values []float64) []float64 {
+	rounded := make([]float64, len(values))
+	for i, value := range values {
+		rounded[i] = math.Round(value*1e9) / 1e9
+		if rounded[i] == 0 {
+			rounded[i] = 0 // Drop the sign of -0
+		}
+	}
+	return rounded
+}
@@ -0,0 +1,206 @@
+package solver
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestAlternativeOptima(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name   string
+		build  func() *model.LinearProgram
+		optima [][]float64
+	}{
+		{
+			// maximize 2x + 4y subject to x + 2y <= 8, x <= 4, y <= 3: the objective is parallel to the first row
+			name: "OptimalEdge",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints: 3,
+					NbVariables:   2,
+					VariableNames: []string{"x", "y"},
+					Objective:     model.MAXIMIZE,
+					ObjCoeff:      []float64{2, 4},
+					Comparisons:   []model.Comparison{model.LE, model.LE, model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+						{1, 2},
+						{1, 0},
+						{0, 1},
+					}),
+					Rhs: []float64{8, 4, 3},
+				}
+			},
+			optima: [][]float64{{2, 3, 16}, {4, 2, 16}},
+		},
+		{
+			// minimize -x - y - z subject to x + y + z <= 1: every vertex of the simplex is optimal
+			name: "OptimalTriangle",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints:   1,
+					NbVariables:     3,
+					VariableNames:   []string{"x", "y", "z"},
+					Objective:       model.MINIMIZE,
+					ObjCoeff:        []float64{-1, -1, -1},
+					Comparisons:     []model.Comparison{model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1, 1}}),
+					Rhs:             []float64{1},
+				}
+			},
+			optima: [][]float64{{0, 0, 1, -1}, {0, 1, 0, -1}, {1, 0, 0, -1}},
+		},
+		{
+			// maximize x + y subject to x + y <= 3 with the bounds x, y <= 2, whose optima sit on the bounds
+			name: "UpperBounds",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints:   1,
+					NbVariables:     2,
+					VariableNames:   []string{"x", "y"},
+					Objective:       model.MAXIMIZE,
+					ObjCoeff:        []float64{1, 1},
+					Comparisons:     []model.Comparison{model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}}),
+					Rhs:             []float64{3},
+					UpperBounds:     []float64{2, 2},
+				}
+			},
+			optima: [][]float64{{1, 2, 3}, {2, 1, 3}},
+		},
+		{
+			// maximize 3x + 5y subject to x <= 4, 2y <= 12, 3x + 2y <= 18 has the single optimum (2, 6)
+			name: "Unique",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints: 3,
+					NbVariables:   2,
+					VariableNames: []string{"x", "y"},
+					Objective:     model.MAXIMIZE,
+					ObjCoeff:      []float64{3, 5},
+					Comparisons:   []model.Comparison{model.LE, model.LE, model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+						{1, 0},
+						{0, 2},
+						{3, 2},
+					}),
+					Rhs: []float64{4, 12, 18},
+				}
+			},
+			optima: [][]float64{{2, 6, 36}},
+		},
+		{
+			// maximize y subject to x + y <= 4, -x + y <= 2 with x free: both parts of x have a zero reduced
+			// cost, but moving them together does not change x
+			name: "UniqueWithFreeVariable",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints: 2,
+					NbVariables:   2,
+					VariableNames: []string{"x", "y"},
+					Objective:     model.MAXIMIZE,
+					ObjCoeff:      []float64{0, 1},
+					Comparisons:   []model.Comparison{model.LE, model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+						{1, 1},
+						{-1, 1},
+					}),
+					Rhs:         []float64{4, 2},
+					LowerBounds: []float64{math.Inf(-1), 0},
+					UpperBounds: []float64{inf, inf},
+				}
+			},
+			optima: [][]float64{{1, 3, 3}},
+		},
+	}
+
+	algorithms := map[string]SolveOptions{
+		"PrimalSimplex":  {Algorithm: PrimalSimplex},
+		"BigM":           {Algorithm: PrimalSimplex, Initialization: BigM},
+		"RevisedSimplex": {Algorithm: RevisedSimplex},
+	}
+
+	for _, tt := range tests {
+		for name, opts := range algorithms {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				lp := tt.build()
+				result, err := SolveWithOptions(context.Background(), lp, opts)
+				if err != nil {
+					t.Fatalf("SolveWithOptions() error = %v", err)
+				}
+				if result.AlternativeOptima != (len(tt.optima) > 1) {
+					t.Errorf("Expected AlternativeOptima = %v, got %v", len(tt.optima) > 1, result.AlternativeOptima)
+				}
+
+				if opts.Algorithm == RevisedSimplex {
+					return // EnumerateOptima always runs on the tableau
+				}
+				problem := tt.build()
+				optima, err := EnumerateOptima(problem, OptimaOptions{LP: opts})
+				if err != nil {
+					t.Fatalf("EnumerateOptima() error = %v", err)
+				}
+				if len(optima) == 0 || !equalFloat64Slices(optima[0], result.ObjVar, 1e-9) {
+					t.Errorf("Expected the first solution to be %v, got %v", result.ObjVar, optima)
+				}
+				if got := sortedSolutions(optima); !reflect.DeepEqual(got, tt.optima) {
+					t.Errorf("Expected the optima %v, got %v", tt.optima, got)
+				}
+				if !reflect.DeepEqual(problem, tt.build()) {
+					t.Errorf("Expected EnumerateOptima() to leave the problem unchanged")
+				}
+			})
+		}
+	}
+}
+
+func TestEnumerateOptima_Limit(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints:   1,
+		NbVariables:     4,
+		VariableNames:   []string{"a", "b", "c", "d"},
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        []float64{1, 1, 1, 1},
+		Comparisons:     []model.Comparison{model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1, 1, 1}}),
+		Rhs:             []float64{1},
+	}
+	all, err := EnumerateOptima(lp, OptimaOptions{})
+	if err != nil || len(all) != 4 {
+		t.Fatalf("Expected the 4 vertices, got %v with error %v", all, err)
+	}
+	limited, err := EnumerateOptima(lp, OptimaOptions{MaxSolutions: 2})
+	if err != nil || len(limited) != 2 {
+		t.Errorf("Expected 2 solutions, got %v with error %v", limited, err)
+	}
+
+	// a + b + c + d >= 5 is infeasible within the bounds 0 <= x <= 1, which the certificate proves
+	lp.Comparisons[0] = model.BE
+	lp.Rhs[0] = 5
+	lp.UpperBounds = []float64{1, 1, 1, 1}
+	if _, err := EnumerateOptima(lp, OptimaOptions{}); CheckCertificate(lp, err) != nil {
+		t.Errorf("Expected an infeasibility certificate, got %v", err)
+	}
+}
+
+// sortedSolutions rounds the solutions and sorts them lexicographically.
+func sortedSolutions(solutions [][]float64) [][]float64 {
+	sorted := make([][]float64, len(solutions))
+	for i, solution := range solutions {
+		sorted[i] = roundedValues(solution)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		for k := range sorted[i] {
+			if sorted[i][k] != sorted[j][k] {
+				return sorted[i][k] < sorted[j][k]
+			}
+		}
+		return false
+	})
+	return sorted
+}
@@ -0,0 +1,69 @@
+package solver
+
+import "errors"
+
+// AddBigMPenalties adds artificial variables for the rows with a negative RHS and
+// penalizes each of them with -penalty in the objective function. The objective row
+// without the penalties is kept in case Phase I has to take over, see optimizeBigM.
+func (table *SimplexTable) AddBigMPenalties(penalty float64) {
+	table.AddArtificialVariables()
+
+	objectiveRow := len(table.data) - 1
+	if len(table.artificials) > 0 {
+		table.phaseTwoObjective = append([]float64(nil), table.data[objectiveRow]...)
+	}
+	for _, col := range table.artificials {
+		table.data[objectiveRow][col] = penalty
+	}
+	table.priceOut()
+}
+
+// CheckArtificialVariables returns an error if an artificial variable is still basic
+// at a positive level, in which case the original problem is infeasible.
+// The Phase I objective is then optimized from the current basis to obtain a Farkas certificate.
+func (table *SimplexTable) CheckArtificialVariables() error {
+	if !table.hasPositiveArtificial() {
+		return nil
+	}
+	table.setPhaseOneObjective()
+	table.phase = TracePhaseOne
+	if err := table.optimize(); err != nil {
+		return err
+	}
+	return table.infeasibleError()
+}
+
+// optimizeBigM optimizes the penalized objective. A ray, or an optimum with an artificial variable still positive,
+// proves nothing while the objective row holds the penalties: the penalty may be too small to make the artificial
+// variables leave first, and its magnitude swamps the original reduced costs. Phase I then decides from the current
+// basis whether the problem is infeasible, and otherwise the original objective is optimized from the feasible
+// basis it found.
+func (table *SimplexTable) optimizeBigM() error {
+	err := table.optimize()
+	if table.phaseTwoObjective == nil {
+		return err
+	}
+
+	var unbounded *UnboundedError
+	if !errors.As(err, &unbounded) && (err != nil || !table.hasPositiveArtificial()) {
+		table.phaseTwoObjective = nil
+		return err
+	}
+	if err := table.minimizeArtificials(); err != nil {
+		return err
+	}
+	return table.optimize()
+}
+
+// hasPositiveArtificial reports whether an artificial variable is still basic at a positive level.
+func (table *SimplexTable) hasPositiveArtificial() bool {
+	rhsCol := len(table.data[0]) - 1
+	epsilon := 1e-9
+
+	for i, basic := range table.basicVariables {
+		if table.isArtificial(int(basic)) && table.data[i][rhsCol] > epsilon {
+			return true
+		}
+	}
+	return false
+}
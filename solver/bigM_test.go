@@ -0,0 +1,126 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestSolveWithOptions_BigM(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints: 3,
+		NbVariables:   2,
+		VariableNames: []string{"x", "y"},
+		Objective:     model.MINIMIZE,
+		ObjCoeff:      []float64{2, 3},
+		Comparisons:   []model.Comparison{model.BE, model.LE, model.EQ},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 1},
+			{2, 1},
+			{1, -1},
+		}),
+		Rhs: []float64{10, 20, 2},
+	}
+
+	result, err := SolveWithOptions(context.Background(), lp, SolveOptions{Initialization: BigM})
+	if err != nil {
+		t.Fatalf("SolveWithOptions() error = %v", err)
+	}
+
+	// Expected solution for this problem is x=6, y=4, objective=24
+	expectedSolution := []float64{6, 4, 24}
+	if !equalFloat64Slices(result.ObjVar, expectedSolution, 1e-6) {
+		t.Errorf("Expected solution to be %v, got %v", expectedSolution, result.ObjVar)
+	}
+}
+
+func TestSolveWithOptions_BigMInfeasible(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints: 2,
+		NbVariables:   2,
+		VariableNames: []string{"x1", "x2"},
+		Objective:     model.MAXIMIZE,
+		ObjCoeff:      []float64{1, 1},
+		Comparisons:   []model.Comparison{model.LE, model.BE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 1},
+			{1, 1},
+		}),
+		Rhs: []float64{1, 2},
+	}
+
+	_, err := SolveWithOptions(context.Background(), lp, SolveOptions{Initialization: BigM, BigMPenalty: 100})
+	if err == nil || err.Error() != "infeasible problem" {
+		t.Errorf("Expected error to be 'infeasible problem', got %v", err)
+	}
+}
+
+func TestSolveWithOptions_BigMRay(t *testing.T) {
+	// max y s.t. x <= 1, x >= 3: y has a ray while the artificial variable of x >= 3 is still positive
+	infeasible := &model.LinearProgram{
+		NbConstraints:   2,
+		NbVariables:     2,
+		VariableNames:   []string{"x", "y"},
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        []float64{0, 1},
+		Comparisons:     []model.Comparison{model.LE, model.BE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 0}, {1, 0}}),
+		Rhs:             []float64{1, 3},
+	}
+
+	// max y s.t. x >= 3 is feasible, so the ray is one of the original problem
+	unbounded := &model.LinearProgram{
+		NbConstraints:   1,
+		NbVariables:     2,
+		VariableNames:   []string{"y", "x"},
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        []float64{1, 0},
+		Comparisons:     []model.Comparison{model.BE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{0, 1}}),
+		Rhs:             []float64{3},
+	}
+
+	// The penalty leaves a reduced cost of about -1e-10 in the optimal tableau of this problem, which looks like a ray
+	roundoff := &model.LinearProgram{
+		NbConstraints: 5,
+		NbVariables:   3,
+		VariableNames: []string{"a", "b", "c"},
+		Objective:     model.MAXIMIZE,
+		ObjCoeff:      []float64{0, 5, -1},
+		Comparisons:   []model.Comparison{model.BE, model.LE, model.EQ, model.BE, model.BE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{-4, -5, 4},
+			{-1, -3, -2},
+			{2, 5, -1},
+			{0, 1, 0},
+			{2, -1, 1},
+		}),
+		Rhs: []float64{0, -7, 5, 4, 8},
+	}
+
+	tests := []struct {
+		name     string
+		lp       *model.LinearProgram
+		status   Status
+		sentinel error
+	}{
+		{"Infeasible", infeasible, Infeasible, ErrInfeasible},
+		{"Unbounded", unbounded, Unbounded, ErrUnbounded},
+		{"Roundoff", roundoff, Optimal, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SolveWithOptions(context.Background(), tt.lp, SolveOptions{Initialization: BigM})
+			if !errors.Is(err, tt.sentinel) || result == nil || result.Status != tt.status {
+				t.Fatalf("Expected the status %v, got %+v and %v", tt.status, result, err)
+			}
+			if tt.status == Optimal && math.Abs(result.Objective-5) > 1e-9 {
+				t.Errorf("Expected the optimum 5, got %v", result.Objective)
+			}
+		})
+	}
+}
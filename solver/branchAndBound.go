@@ -0,0 +1,261 @@
+package solver
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// NodeSelection selects the next node of the branch-and-bound tree to solve.
+type NodeSelection int
+
+const (
+	BestBound  NodeSelection = iota // Node whose relaxation promises the best objective
+	DepthFirst                      // Most recently created node, which finds integer solutions quickly
+)
+
+// DefaultIntegralityTolerance is the distance to the nearest integer under which a value counts as integer
+// when MIPOptions.IntegralityTolerance is not set.
+const DefaultIntegralityTolerance = 1e-6
+
+// MIPOptions configures a call to SolveMIP.
+type MIPOptions struct {
+	LP                   SolveOptions // Options of the LP relaxations
+	NodeSelection        NodeSelection
+	IntegralityTolerance float64 // Distance to the nearest integer under which a value counts as integer
+	GapTolerance         float64 // Relative gap under which the incumbent is accepted as optimal
+	MaxNodes             int     // Maximum number of nodes to solve, 0 means no limit
+	RootCutRounds        int     // Rounds of Gomory cuts added at the root before branching, see SolveCuttingPlanes
+
+	Log func(MIPStats) // Called whenever a better integer solution is found
+}
+
+// MIPStats reports the progress of the branch and bound.
+type MIPStats struct {
+	Nodes     int     // Relaxations solved so far
+	Incumbent float64 // Objective value of the best integer solution found, NaN if there is none yet
+	BestBound float64 // Best objective value that any integer solution can reach
+	Gap       float64 // |BestBound - Incumbent| / max(1, |Incumbent|), +Inf without incumbent
+}
+
+func (opts MIPOptions) integralityTolerance() float64 {
+	if opts.IntegralityTolerance <= 0 {
+		return DefaultIntegralityTolerance
+	}
+	return opts.IntegralityTolerance
+}
+
+// node is a subproblem of the branch and bound: the original problem with tightened bounds.
+type node struct {
+	lower, upper []float64
+	bound        float64 // Maximized objective of the parent relaxation, which bounds the node
+	basis        *model.Basis
+}
+
+// nodeQueue holds the open nodes, ordered by bound for BestBound and as a stack for DepthFirst.
+type nodeQueue struct {
+	nodes     []*node
+	bestBound bool
+}
+
+func (q *nodeQueue) Len() int           { return len(q.nodes) }
+func (q *nodeQueue) Less(i, j int) bool { return q.nodes[i].bound > q.nodes[j].bound }
+func (q *nodeQueue) Swap(i, j int)      { q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i] }
+func (q *nodeQueue) Push(x any)         { q.nodes = append(q.nodes, x.(*node)) }
+func (q *nodeQueue) Pop() any {
+	last := q.nodes[len(q.nodes)-1]
+	q.nodes = q.nodes[:len(q.nodes)-1]
+	return last
+}
+
+// push adds a node. Depth-first search pops the last pushed node, so it does not go through the heap.
+func (q *nodeQueue) push(n *node) {
+	if q.bestBound {
+		heap.Push(q, n)
+	} else {
+		q.nodes = append(q.nodes, n)
+	}
+}
+
+// pop removes the next node to solve.
+func (q *nodeQueue) pop() *node {
+	if q.bestBound {
+		return heap.Pop(q).(*node)
+	}
+	return q.Pop().(*node)
+}
+
+// maxBound returns the largest bound of the open nodes, -Inf if there are none.
+func (q *nodeQueue) maxBound() float64 {
+	bound := math.Inf(-1)
+	for _, n := range q.nodes {
+		bound = math.Max(bound, n.bound)
+	}
+	return bound
+}
+
+// SolveMIP solves a problem whose integer and binary variables (see LinearProgram.Types) must take integer
+// values. Branch and bound solves the LP relaxation of each node like SolveWithOptions, branches on the most
+// fractional integer variable by tightening its bounds, and prunes the nodes whose relaxation cannot beat the
+// best integer solution found. Each node starts from the final basis of its parent. With RootCutRounds, Gomory
+// cuts tighten the relaxation of the root first. Like SolveWithOptions, it solves copies of the problem and
+// returns the best integer solution as a *Result, whose MIP field holds the final MIPStats.
+// With MaxNodes, the solve stops with a *LimitError matching ErrIterationLimit once the limit is reached; the
+// Result then has the IterationLimit status and holds the best solution found so far, if any, with its gap.
+func SolveMIP(lp *model.LinearProgram, opts MIPOptions) (*Result, error) {
+	start := time.Now()
+	stats := &MIPStats{Incumbent: math.NaN(), BestBound: math.NaN(), Gap: math.Inf(1)}
+	iterations := 0
+	var incumbent []float64
+	finish := func(err error) (*Result, error) {
+		status, ok := statusOf(err)
+		if !ok {
+			return nil, err
+		}
+		result := &Result{Status: status, Iterations: iterations, Duration: time.Since(start), MIP: stats}
+		if incumbent != nil {
+			result.Objective, result.ObjVar = incumbent[lp.NbVariables], incumbent
+			result.Values = make(map[string]float64, lp.NbVariables)
+			for j, value := range incumbent[:lp.NbVariables] {
+				result.Values[lp.VariableName(j)] = value
+			}
+			result.solution = lp.Clone()
+			result.solution.ObjVar = incumbent
+		}
+		return result, err
+	}
+
+	err := checkBounds(lp)
+	if err != nil {
+		return finish(err)
+	}
+
+	sense := 1.0
+	if lp.Objective == model.MINIMIZE {
+		sense = -1
+	}
+	tolerance := opts.integralityTolerance()
+
+	base := lp.Clone()
+	if opts.RootCutRounds > 0 {
+		cutStats, err := addGomoryCuts(base, opts.LP, opts.RootCutRounds, tolerance)
+		var infeasible *InfeasibleError
+		if errors.As(err, &infeasible) && cutStats.Rounds > 0 {
+			return finish(&InfeasibleError{}) // The cuts exclude no integer solution, so none exists
+		}
+		if err != nil {
+			return finish(err)
+		}
+	}
+
+	root := &node{bound: math.Inf(1), basis: opts.LP.WarmStart}
+	root.lower = make([]float64, lp.NbVariables)
+	root.upper = make([]float64, lp.NbVariables)
+	for j := range root.lower {
+		root.lower[j], root.upper[j] = lp.Bounds(j)
+	}
+	queue := &nodeQueue{bestBound: opts.NodeSelection == BestBound}
+	queue.push(root)
+
+	incumbentScore := math.Inf(-1)
+
+	// The incumbent only needs to be beaten by more than the gap tolerance
+	prunes := func(bound float64) bool {
+		return incumbent != nil && bound <= incumbentScore+opts.GapTolerance*math.Max(1, math.Abs(incumbentScore))+1e-9
+	}
+	report := func(bound float64) {
+		stats.Incumbent, stats.BestBound = sense*incumbentScore, sense*bound
+		stats.Gap = math.Abs(bound-incumbentScore) / math.Max(1, math.Abs(incumbentScore))
+	}
+
+	for queue.Len() > 0 {
+		if opts.MaxNodes > 0 && stats.Nodes >= opts.MaxNodes {
+			break
+		}
+		current := queue.pop()
+		if prunes(current.bound) {
+			continue
+		}
+
+		relaxation := base.Clone()
+		relaxation.Signs = nil
+		relaxation.LowerBounds = append([]float64(nil), current.lower...)
+		relaxation.UpperBounds = append([]float64(nil), current.upper...)
+		lpOpts := opts.LP
+		lpOpts.WarmStart = current.basis
+		lim := newLimits(context.Background(), lpOpts)
+		err := solve(relaxation, lpOpts, lim)
+		stats.Nodes++
+		iterations += lim.iterations
+
+		var infeasible *InfeasibleError
+		if errors.As(err, &infeasible) && stats.Nodes > 1 {
+			continue // The certificate of an infeasible root relaxation is returned with its error
+		}
+		if err != nil {
+			return finish(err)
+		}
+		values := relaxation.ObjVar[:lp.NbVariables]
+		score := sense * relaxation.ObjVar[lp.NbVariables]
+		if prunes(score) {
+			continue
+		}
+
+		// Branch on the integer variable farthest from an integer
+		branch, distance := -1, tolerance
+		for j, value := range values {
+			if lp.IsInteger(j) {
+				if d := math.Abs(value - math.Round(value)); d > distance {
+					branch, distance = j, d
+				}
+			}
+		}
+
+		if branch == -1 {
+			incumbent = append([]float64(nil), values...)
+			for j := range incumbent {
+				if lp.IsInteger(j) {
+					incumbent[j] = math.Round(incumbent[j])
+				}
+			}
+			incumbent = append(incumbent, relaxation.ObjVar[lp.NbVariables])
+			incumbentScore = score
+			report(math.Max(score, queue.maxBound()))
+			if opts.Log != nil {
+				opts.Log(*stats)
+			}
+			continue
+		}
+
+		down := &node{lower: current.lower, upper: append([]float64(nil), current.upper...), bound: score, basis: relaxation.Basis}
+		down.upper[branch] = math.Floor(values[branch])
+		up := &node{lower: append([]float64(nil), current.lower...), upper: current.upper, bound: score, basis: relaxation.Basis}
+		up.lower[branch] = math.Ceil(values[branch])
+
+		// Depth-first search dives into the child on the side the value is rounded to
+		if values[branch]-math.Floor(values[branch]) < 0.5 {
+			queue.push(up)
+			queue.push(down)
+		} else {
+			queue.push(down)
+			queue.push(up)
+		}
+	}
+
+	// The node limit only stops the search if an open node could still beat the incumbent
+	bound := math.Max(incumbentScore, queue.maxBound())
+	if incumbent != nil {
+		report(bound)
+	}
+	if queue.Len() > 0 && !prunes(bound) {
+		return finish(&LimitError{Status: IterationLimit, Iterations: iterations})
+	}
+	if incumbent == nil {
+		return finish(&InfeasibleError{})
+	}
+	return finish(nil)
+}
@@ -0,0 +1,197 @@
+package solver
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestSolveMIP(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func() *model.LinearProgram
+		solution []float64
+	}{
+		{
+			// Knapsack: maximize 10a + 13b + 7c + 8d subject to 3a + 4b + 2c + 3d <= 7, binary variables
+			name: "BinaryKnapsack",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints:   1,
+					NbVariables:     4,
+					VariableNames:   []string{"a", "b", "c", "d"},
+					Objective:       model.MAXIMIZE,
+					ObjCoeff:        []float64{10, 13, 7, 8},
+					Comparisons:     []model.Comparison{model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{3, 4, 2, 3}}),
+					Rhs:             []float64{7},
+					Types:           []model.VariableType{model.Binary, model.Binary, model.Binary, model.Binary},
+				}
+			},
+			solution: []float64{1, 1, 0, 0, 23},
+		},
+		{
+			// maximize 5x + 8y subject to x + y <= 6, 5x + 9y <= 45, whose relaxation optimum is (2.25, 3.75)
+			name: "GeneralInteger",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints: 2,
+					NbVariables:   2,
+					VariableNames: []string{"x", "y"},
+					Objective:     model.MAXIMIZE,
+					ObjCoeff:      []float64{5, 8},
+					Comparisons:   []model.Comparison{model.LE, model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+						{1, 1},
+						{5, 9},
+					}),
+					Rhs:   []float64{6, 45},
+					Types: []model.VariableType{model.Integer, model.Integer},
+				}
+			},
+			solution: []float64{0, 5, 40},
+		},
+		{
+			// minimize 4x + 3y subject to 2x + y >= 3.5, x + 3y >= 3 with x integer and y continuous
+			name: "MixedMinimize",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints: 2,
+					NbVariables:   2,
+					VariableNames: []string{"x", "y"},
+					Objective:     model.MINIMIZE,
+					ObjCoeff:      []float64{4, 3},
+					Comparisons:   []model.Comparison{model.BE, model.BE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+						{2, 1},
+						{1, 3},
+					}),
+					Rhs:   []float64{3.5, 3},
+					Types: []model.VariableType{model.Integer, model.Continuous},
+				}
+			},
+			solution: []float64{1, 1.5, 8.5},
+		},
+	}
+
+	options := map[string]MIPOptions{
+		"BestBound":              {NodeSelection: BestBound},
+		"DepthFirst":             {NodeSelection: DepthFirst},
+		"DepthFirstRevised":      {NodeSelection: DepthFirst, LP: SolveOptions{Algorithm: RevisedSimplex}},
+		"BestBoundSteepestEdge":  {NodeSelection: BestBound, LP: SolveOptions{Pricing: SteepestEdgePricing}},
+		"BestBoundInteriorPoint": {NodeSelection: BestBound, LP: SolveOptions{Algorithm: InteriorPoint}},
+	}
+
+	for _, tt := range tests {
+		for name, opts := range options {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				lp := tt.build()
+				var incumbents []float64
+				opts.Log = func(stats MIPStats) {
+					incumbents = append(incumbents, stats.Incumbent)
+				}
+
+				result, err := SolveMIP(lp, opts)
+				if err != nil {
+					t.Fatalf("SolveMIP() error = %v", err)
+				}
+				if result.Status != Optimal || !equalFloat64Slices(result.ObjVar, tt.solution, 1e-6) {
+					t.Errorf("Expected %v, got %v with status %v", tt.solution, result.ObjVar, result.Status)
+				}
+				stats := result.MIP
+				objective := tt.solution[len(tt.solution)-1]
+				if stats.Gap > 1e-9 || !equalFloat64Slices([]float64{stats.Incumbent, stats.BestBound}, []float64{objective, objective}, 1e-6) {
+					t.Errorf("Expected a closed gap at %v, got %+v", objective, stats)
+				}
+				if len(incumbents) == 0 || incumbents[len(incumbents)-1] != stats.Incumbent {
+					t.Errorf("Expected every incumbent to be logged, got %v", incumbents)
+				}
+
+				if !reflect.DeepEqual(lp, tt.build()) {
+					t.Errorf("Expected SolveMIP() to leave the problem unchanged")
+				}
+			})
+		}
+	}
+}
+
+func TestSolveMIP_Infeasible(t *testing.T) {
+	// 2x = 1 has no integer solution, although its relaxation is feasible
+	lp := &model.LinearProgram{
+		NbConstraints:   1,
+		NbVariables:     2,
+		VariableNames:   []string{"x", "y"},
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        []float64{1, 1},
+		Comparisons:     []model.Comparison{model.EQ},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{2, 0}}),
+		Rhs:             []float64{1},
+		UpperBounds:     []float64{10, 10},
+		Types:           []model.VariableType{model.Integer, model.Continuous},
+	}
+	var infeasible *InfeasibleError
+	if _, err := SolveMIP(lp, MIPOptions{}); !errors.As(err, &infeasible) {
+		t.Errorf("Expected an InfeasibleError, got %v", err)
+	}
+
+	// An infeasible relaxation keeps its certificate
+	lp.Rhs = []float64{40}
+	_, err := SolveMIP(lp, MIPOptions{})
+	if !errors.As(err, &infeasible) || infeasible.Farkas == nil {
+		t.Fatalf("Expected an InfeasibleError with a certificate, got %v", err)
+	}
+	if err := CheckCertificate(lp, err); err != nil {
+		t.Errorf("CheckCertificate() error = %v", err)
+	}
+}
+
+func TestSolveMIP_NodeLimit(t *testing.T) {
+	build := func() *model.LinearProgram {
+		return &model.LinearProgram{
+			NbConstraints: 2,
+			NbVariables:   2,
+			VariableNames: []string{"x", "y"},
+			Objective:     model.MAXIMIZE,
+			ObjCoeff:      []float64{5, 8},
+			Comparisons:   []model.Comparison{model.LE, model.LE},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+				{1, 1},
+				{5, 9},
+			}),
+			Rhs:   []float64{6, 45},
+			Types: []model.VariableType{model.Integer, model.Integer},
+		}
+	}
+
+	result, err := SolveMIP(build(), MIPOptions{MaxNodes: 1})
+	var limitErr *LimitError
+	if !errors.Is(err, ErrIterationLimit) || !errors.As(err, &limitErr) || result.Status != IterationLimit || result.ObjVar != nil {
+		t.Errorf("Expected an iteration limit without integer solution within the node limit, got %v", err)
+	}
+
+	// Depth-first search finds an integer solution early, which is returned with its gap
+	var first *MIPStats
+	opts := MIPOptions{NodeSelection: DepthFirst, Log: func(stats MIPStats) {
+		if first == nil {
+			first = &stats
+		}
+	}}
+	if _, err := SolveMIP(build(), opts); err != nil || first == nil {
+		t.Fatalf("SolveMIP() error = %v", err)
+	}
+
+	opts.MaxNodes = first.Nodes
+	result, err = SolveMIP(build(), opts)
+	if !errors.Is(err, ErrIterationLimit) || result.Status != IterationLimit {
+		t.Fatalf("Expected the node limit to stop SolveMIP(), got %v", err)
+	}
+	stats := result.MIP
+	if stats.Nodes != first.Nodes || stats.Incumbent != first.Incumbent || stats.Incumbent != result.Objective || result.ObjVar[2] != result.Objective {
+		t.Errorf("Expected the first incumbent %+v, got %+v for the solution %v", first, stats, result.ObjVar)
+	}
+	if stats.BestBound < stats.Incumbent || stats.BestBound > 41.25 || stats.Gap != (stats.BestBound-stats.Incumbent)/stats.Incumbent {
+		t.Errorf("Unexpected bound and gap %+v", stats)
+	}
+}
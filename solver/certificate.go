@@ -0,0 +1,173 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// InfeasibleError is returned for an infeasible problem. Farkas holds one multiplier y_i per original
+// constraint, non-negative for <= constraints and non-positive for >= constraints, such that y*(Ax - b) > 0
+// for every x within the bounds of the variables. No x can then satisfy all the constraints.
+// Farkas is nil when no certificate is available: for a variable whose lower bound is above its upper bound, from
+// InteriorPointSolver.Solve, which SolveWithOptions confirms with the simplex method, or for a MIP without integer
+// solution.
+type InfeasibleError struct {
+	Farkas []float64
+}
+
+func (e *InfeasibleError) Error() string {
+	return ErrInfeasible.Error()
+}
+
+// Is makes errors.Is(err, ErrInfeasible) hold.
+func (e *InfeasibleError) Is(target error) bool {
+	return target == ErrInfeasible
+}
+
+// checkBounds returns an error for bounds that are not numbers, and an *InfeasibleError for a variable whose lower
+// bound is above its upper bound.
+func checkBounds(lp *model.LinearProgram) error {
+	err := lp.CheckBounds()
+	if errors.Is(err, model.ErrCrossedBounds) {
+		return &InfeasibleError{}
+	}
+	return err
+}
+
+// UnboundedError is returned for an unbounded problem. Ray holds a direction over the original variables
+// along which every constraint and bound stays satisfied while the objective improves, so that any feasible
+// point can be moved along it without limit. Ray is nil from InteriorPointSolver.Solve.
+type UnboundedError struct {
+	Ray []float64
+}
+
+func (e *UnboundedError) Error() string {
+	return ErrUnbounded.Error()
+}
+
+// Is makes errors.Is(err, ErrUnbounded) hold.
+func (e *UnboundedError) Is(target error) bool {
+	return target == ErrUnbounded
+}
+
+// originalCertificate maps the certificate carried by an error from the rows and columns of the slack form
+// to the original constraints and variables.
+func originalCertificate(lp *model.LinearProgram, err error) error {
+	var infeasible *InfeasibleError
+	if errors.As(err, &infeasible) && infeasible.Farkas != nil {
+		infeasible.Farkas = lp.OriginalMultipliers(infeasible.Farkas)
+	}
+	var unbounded *UnboundedError
+	if errors.As(err, &unbounded) && unbounded.Ray != nil {
+		unbounded.Ray = lp.OriginalDirection(unbounded.Ray)
+	}
+	return err
+}
+
+// CheckCertificate verifies the Farkas certificate or the unbounded ray carried by an error of
+// SolveWithOptions against the problem as it was stated, which SolveWithOptions leaves unchanged. The problem must
+// not be converted. It returns nil if the certificate holds.
+func CheckCertificate(lp *model.LinearProgram, err error) error {
+	if lp.State != model.Undefined {
+		return fmt.Errorf("certificates must be checked against the problem before its conversion")
+	}
+
+	var infeasible *InfeasibleError
+	if errors.As(err, &infeasible) {
+		return checkFarkas(lp, infeasible.Farkas)
+	}
+	var unbounded *UnboundedError
+	if errors.As(err, &unbounded) {
+		return checkRay(lp, unbounded.Ray)
+	}
+	return fmt.Errorf("no certificate in error: %v", err)
+}
+
+// checkFarkas verifies that min y*(Ax - b) over the bounds of the variables is positive.
+func checkFarkas(lp *model.LinearProgram, farkas []float64) error {
+	epsilon := 1e-9
+
+	if len(farkas) != lp.NbConstraints {
+		return fmt.Errorf("expected %d multipliers, got %d", lp.NbConstraints, len(farkas))
+	}
+	for i, y := range farkas {
+		switch lp.Comparisons[i] {
+		case model.LE, model.LO:
+			if y < -epsilon {
+				return fmt.Errorf("multiplier %d of a <= constraint is negative", i+1)
+			}
+		case model.BE, model.BI:
+			if y > epsilon {
+				return fmt.Errorf("multiplier %d of a >= constraint is positive", i+1)
+			}
+		}
+	}
+
+	minimum := -dot(farkas, lp.Rhs)
+	columns := lp.ConstraintCoeff.Transpose()
+	for j := 0; j < lp.NbVariables; j++ {
+		coeff := columns.Rows[j].Dot(farkas)
+		if math.Abs(coeff) <= epsilon {
+			continue
+		}
+		lower, upper := lp.Bounds(j)
+		bound := lower
+		if coeff < 0 {
+			bound = upper
+		}
+		if math.IsInf(bound, 0) {
+			return fmt.Errorf("the combined constraint is unbounded in variable %d", j+1)
+		}
+		minimum += coeff * bound
+	}
+
+	if minimum <= epsilon {
+		return fmt.Errorf("the combined constraint can be satisfied, min y*(Ax - b) = %g", minimum)
+	}
+	return nil
+}
+
+// checkRay verifies that the ray keeps every constraint and bound satisfied and improves the objective.
+func checkRay(lp *model.LinearProgram, ray []float64) error {
+	epsilon := 1e-9
+
+	if len(ray) != lp.NbVariables {
+		return fmt.Errorf("expected %d ray components, got %d", lp.NbVariables, len(ray))
+	}
+	for j, d := range ray {
+		lower, upper := lp.Bounds(j)
+		if (d > epsilon && !math.IsInf(upper, 1)) || (d < -epsilon && !math.IsInf(lower, -1)) {
+			return fmt.Errorf("the ray leaves the bounds of variable %d", j+1)
+		}
+	}
+
+	for i := 0; i < lp.NbConstraints; i++ {
+		change := lp.ConstraintCoeff.Rows[i].Dot(ray)
+		switch lp.Comparisons[i] {
+		case model.LE, model.LO:
+			if change > epsilon {
+				return fmt.Errorf("the ray increases the left-hand side of <= constraint %d", i+1)
+			}
+		case model.BE, model.BI:
+			if change < -epsilon {
+				return fmt.Errorf("the ray decreases the left-hand side of >= constraint %d", i+1)
+			}
+		default:
+			if math.Abs(change) > epsilon {
+				return fmt.Errorf("the ray changes the left-hand side of equality %d", i+1)
+			}
+		}
+	}
+
+	improvement := dot(lp.ObjCoeff, ray)
+	if lp.Objective == model.MINIMIZE {
+		improvement *= -1
+	}
+	if improvement <= epsilon {
+		return fmt.Errorf("the ray does not improve the objective")
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestSolve_Certificates(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name       string
+		lp         *model.LinearProgram
+		algorithms []SolveOptions
+		unbounded  bool
+	}{
+		{
+			// x1 + x2 <= 2 and x1 + x2 >= 5
+			name: "InfeasibleRows",
+			lp: &model.LinearProgram{
+				NbConstraints: 2,
+				NbVariables:   2,
+				VariableNames: []string{"x1", "x2"},
+				Objective:     model.MINIMIZE,
+				ObjCoeff:      []float64{1, 1},
+				Comparisons:   []model.Comparison{model.LE, model.BE},
+				ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+					{1, 1},
+					{1, 1},
+				}),
+				Rhs: []float64{2, 5},
+			},
+			algorithms: []SolveOptions{
+				{Algorithm: PrimalSimplex},
+				{Algorithm: PrimalSimplex, Initialization: BigM},
+				{Algorithm: DualSimplex},
+				{Algorithm: RevisedSimplex},
+			},
+		},
+		{
+			// x + y = 10 with x in [2, 3] and y free but y <= 4
+			name: "InfeasibleBoundsAndEquality",
+			lp: &model.LinearProgram{
+				NbConstraints:   2,
+				NbVariables:     2,
+				VariableNames:   []string{"x", "y"},
+				Objective:       model.MAXIMIZE,
+				ObjCoeff:        []float64{1, 0},
+				Comparisons:     []model.Comparison{model.EQ, model.LE},
+				ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}, {0, 1}}),
+				Rhs:             []float64{10, 4},
+				Signs:           []model.Sign{model.NonNegative, model.Free},
+				LowerBounds:     []float64{2, -inf},
+				UpperBounds:     []float64{3, inf},
+			},
+			algorithms: []SolveOptions{
+				{Algorithm: PrimalSimplex},
+				{Algorithm: PrimalSimplex, Initialization: BigM},
+				{Algorithm: RevisedSimplex},
+			},
+		},
+		{
+			// maximize x1 + x2 subject to x1 - x2 <= 1
+			name: "UnboundedRay",
+			lp: &model.LinearProgram{
+				NbConstraints:   1,
+				NbVariables:     2,
+				VariableNames:   []string{"x1", "x2"},
+				Objective:       model.MAXIMIZE,
+				ObjCoeff:        []float64{1, 1},
+				Comparisons:     []model.Comparison{model.LE},
+				ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, -1}}),
+				Rhs:             []float64{1},
+			},
+			algorithms: []SolveOptions{
+				{Algorithm: PrimalSimplex},
+				{Algorithm: PrimalSimplex, Pricing: DantzigPricing},
+				{Algorithm: RevisedSimplex},
+			},
+			unbounded: true,
+		},
+		{
+			// minimize x - y subject to x + y >= 1, x - y - z >= -3 with x <= 5, y free and z non-positive
+			name: "UnboundedAfterPhaseOne",
+			lp: &model.LinearProgram{
+				NbConstraints: 2,
+				NbVariables:   3,
+				VariableNames: []string{"x", "y", "z"},
+				Objective:     model.MINIMIZE,
+				ObjCoeff:      []float64{1, -1, 0},
+				Comparisons:   []model.Comparison{model.BE, model.BE},
+				ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+					{1, 1, 0},
+					{1, -1, -1},
+				}),
+				Rhs:         []float64{1, -3},
+				Signs:       []model.Sign{model.NonNegative, model.Free, model.NonPositive},
+				UpperBounds: []float64{5, inf, 0},
+			},
+			algorithms: []SolveOptions{
+				{Algorithm: PrimalSimplex},
+				{Algorithm: PrimalSimplex, Initialization: BigM},
+				{Algorithm: RevisedSimplex},
+			},
+			unbounded: true,
+		},
+	}
+
+	for _, tt := range tests {
+		for k, opts := range tt.algorithms {
+			t.Run(fmt.Sprintf("%s/%d", tt.name, k), func(t *testing.T) {
+				lp := tt.lp.Clone()
+				_, err := SolveWithOptions(context.Background(), lp, opts)
+
+				var infeasible *InfeasibleError
+				var unbounded *UnboundedError
+				if tt.unbounded {
+					if !errors.As(err, &unbounded) || err.Error() != "unbounded problem" {
+						t.Fatalf("Expected an UnboundedError, got %v", err)
+					}
+				} else if !errors.As(err, &infeasible) || err.Error() != "infeasible problem" {
+					t.Fatalf("Expected an InfeasibleError, got %v", err)
+				}
+
+				if err := CheckCertificate(tt.lp, err); err != nil {
+					t.Errorf("CheckCertificate() error = %v (farkas %v, ray %v)", err, infeasible, unbounded)
+				}
+			})
+		}
+	}
+}
+
+func TestCheckCertificate_Rejects(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints:   2,
+		NbVariables:     2,
+		VariableNames:   []string{"x1", "x2"},
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        []float64{1, 1},
+		Comparisons:     []model.Comparison{model.LE, model.BE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, -1}, {1, 1}}),
+		Rhs:             []float64{1, 5},
+	}
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"NoCertificate", fmt.Errorf("infeasible problem")},
+		{"WrongLength", &InfeasibleError{Farkas: []float64{1}}},
+		{"WrongSign", &InfeasibleError{Farkas: []float64{-1, 0}}},
+		{"SatisfiableCombination", &InfeasibleError{Farkas: []float64{1, -1}}},
+		{"RayViolatesConstraint", &UnboundedError{Ray: []float64{1, 0}}},
+		{"RayLeavesBounds", &UnboundedError{Ray: []float64{-1, -1}}},
+		{"RayDoesNotImprove", &UnboundedError{Ray: []float64{0, 0}}},
+	}
+	for _, tt := range tests {
+		if err := CheckCertificate(lp, tt.err); err == nil {
+			t.Errorf("%s: Expected CheckCertificate() to reject %v", tt.name, tt.err)
+		}
+	}
+
+	if err := CheckCertificate(lp, &UnboundedError{Ray: []float64{1, 1}}); err != nil {
+		t.Errorf("Expected the ray (1, 1) to be accepted, got %v", err)
+	}
+
+	converted := lp.Clone()
+	converted.ToSlackForm()
+	if err := CheckCertificate(converted, &UnboundedError{Ray: []float64{1, 1}}); err == nil {
+		t.Errorf("Expected CheckCertificate() to reject a converted problem")
+	}
+}
@@ -0,0 +1,113 @@
+package solver
+
+import "math"
+
+// IsDualFeasible checks if every reduced cost in the objective row is non-negative,
+// which makes the current basis a valid starting point for the dual simplex.
+func (table *SimplexTable) IsDualFeasible() bool {
+	objectiveRow := len(table.data) - 1
+	epsilon := 1e-10
+
+	for j := 0; j < len(table.data[objectiveRow])-1; j++ {
+		if !table.isArtificial(j) && table.data[objectiveRow][j] < -epsilon {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FindDualLeavingVariable finds the leaving row of the dual simplex.
+// It picks the row whose basic variable violates its bounds the most (a negative RHS,
+// or a value above its upper bound) and returns -1 if the basis is primal feasible.
+func (table *SimplexTable) FindDualLeavingVariable() int {
+	numConstraintRows := len(table.data) - 1
+	rhsCol := len(table.data[0]) - 1
+	largestViolation := 1e-10
+	pivotRow := -1
+
+	for i := 0; i < numConstraintRows; i++ {
+		violation := -table.data[i][rhsCol]
+		if aboveUpper := table.data[i][rhsCol] - table.upperBound(int(table.basicVariables[i])); aboveUpper > violation {
+			violation = aboveUpper
+		}
+		if violation > largestViolation {
+			largestViolation = violation
+			pivotRow = i
+		}
+	}
+
+	return pivotRow
+}
+
+// complementBasic substitutes x' = u - x for the basic variable of the row,
+// which turns a value above its upper bound into a negative RHS.
+func (table *SimplexTable) complementBasic(row int) {
+	rhsCol := len(table.data[0]) - 1
+	col := int(table.basicVariables[row])
+
+	for j := range table.data[row] {
+		if j != col {
+			table.data[row][j] *= -1
+		}
+	}
+	table.data[row][rhsCol] += table.upperBound(col)
+	table.atUpper[col] = !table.atUpper[col]
+}
+
+// FindDualEnteringVariable finds the entering variable with the dual ratio test.
+// Among the columns with a negative entry in the pivot row it selects the one with the smallest
+// |reduced cost / entry|, ties going to the smallest index. It returns -1 if there is none.
+func (table *SimplexTable) FindDualEnteringVariable(pivotRow int) int {
+	objectiveRow := len(table.data) - 1
+	smallestRatio := math.Inf(1)
+	pivotCol := -1
+	epsilon := 1e-10
+
+	for j := 0; j < len(table.data[pivotRow])-1; j++ {
+		if table.isArtificial(j) {
+			continue
+		}
+		pivotRowValue := table.data[pivotRow][j]
+		if pivotRowValue < -epsilon {
+			ratio := math.Abs(table.data[objectiveRow][j] / pivotRowValue)
+			if ratio < smallestRatio-epsilon {
+				smallestRatio = ratio
+				pivotCol = j
+			}
+		}
+	}
+
+	return pivotCol
+}
+
+// DualSimplex runs dual simplex iterations from a dual feasible basis until every basic variable is within its bounds.
+// A leaving row without a negative entry proves that the problem is infeasible:
+// the multipliers that combine the rows of the slack form into it are the Farkas certificate.
+func (table *SimplexTable) DualSimplex() error {
+	table.phase = TraceDualSimplex
+	for {
+		pivotRow := table.FindDualLeavingVariable()
+		if pivotRow == -1 {
+			return nil
+		}
+		if err := table.limits.step(); err != nil {
+			return err
+		}
+		if table.data[pivotRow][len(table.data[0])-1] > 0 {
+			table.complementBasic(pivotRow) // Above its upper bound
+		}
+
+		pivotCol := table.FindDualEnteringVariable(pivotRow)
+		if table.trace != nil {
+			table.record(table.dualStep(pivotRow, pivotCol))
+		}
+		if pivotCol == -1 {
+			return &InfeasibleError{Farkas: table.rowMultipliers(pivotRow)}
+		}
+
+		table.PerformPivot(pivotRow, pivotCol)
+
+		table.basicVariables[pivotRow] = float64(pivotCol)
+	}
+}
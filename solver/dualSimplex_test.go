@@ -0,0 +1,104 @@
+package solver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestSolveWithOptions_DualSimplex(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints: 2,
+		NbVariables:   2,
+		VariableNames: []string{"x", "y"},
+		Objective:     model.MINIMIZE,
+		ObjCoeff:      []float64{2, 3},
+		Comparisons:   []model.Comparison{model.BE, model.BE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 1},
+			{1, 3},
+		}),
+		Rhs: []float64{10, 15},
+	}
+
+	result, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: DualSimplex})
+	if err != nil {
+		t.Fatalf("SolveWithOptions() error = %v", err)
+	}
+
+	// Expected solution for this problem is x=7.5, y=2.5, objective=22.5
+	expectedSolution := []float64{7.5, 2.5, 22.5}
+	if !equalFloat64Slices(result.ObjVar, expectedSolution, 1e-9) {
+		t.Errorf("Expected solution to be %v, got %v", expectedSolution, result.ObjVar)
+	}
+}
+
+func TestSolveWithOptions_DualSimplexErrors(t *testing.T) {
+	t.Run("Infeasible", func(t *testing.T) {
+		lp := &model.LinearProgram{
+			NbConstraints:   2,
+			NbVariables:     1,
+			VariableNames:   []string{"x"},
+			Objective:       model.MINIMIZE,
+			ObjCoeff:        []float64{1},
+			Comparisons:     []model.Comparison{model.BE, model.LE},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1}, {1}}),
+			Rhs:             []float64{2, 1},
+		}
+		_, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: DualSimplex})
+		if err == nil || err.Error() != "infeasible problem" {
+			t.Errorf("Expected error to be 'infeasible problem', got %v", err)
+		}
+	})
+
+	t.Run("NotDualFeasible", func(t *testing.T) {
+		lp := &model.LinearProgram{
+			NbConstraints:   1,
+			NbVariables:     1,
+			VariableNames:   []string{"x"},
+			Objective:       model.MAXIMIZE,
+			ObjCoeff:        []float64{1},
+			Comparisons:     []model.Comparison{model.LE},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1}}),
+			Rhs:             []float64{1},
+		}
+		_, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: DualSimplex})
+		if err == nil {
+			t.Errorf("Expected an error for a basis that is not dual feasible, got nil")
+		}
+	})
+}
+
+func TestDualSimplex_Reoptimize(t *testing.T) {
+	// Optimal tableau of max 3x + 2y s.t. x + y <= 4, x <= 3 (x=3, y=1)
+	table := &SimplexTable{
+		data: [][]float64{
+			{0, 1, 1, -1, 1},
+			{1, 0, 0, 1, 3},
+			{0, 0, 2, 1, 11},
+		},
+		basicVariables: []float64{1, 0},
+	}
+
+	// Tighten x <= 3 to x <= 2: the RHS column becomes B^-1 b = (2, 2)
+	table.data[0][4] = 2
+	table.data[1][4] = 2
+	table.data[2][4] = 10
+	if err := table.DualSimplex(); err != nil {
+		t.Fatalf("DualSimplex() error = %v", err)
+	}
+	if table.data[2][4] != 10 || table.data[0][4] != 2 || table.data[1][4] != 2 {
+		t.Errorf("Expected the tightened basis to stay optimal, got %v", table.data)
+	}
+
+	// Make x + y <= 4 a x + y <= 1 with x <= 2: basic values become y = -1, x = 2
+	table.data[0][4] = -1
+	table.data[2][4] = 4
+	if err := table.DualSimplex(); err != nil {
+		t.Fatalf("DualSimplex() error = %v", err)
+	}
+	if !table.IsInitiallyFeasible() || table.data[2][4] != 3 {
+		t.Errorf("Expected objective 3 after re-optimization, got %v", table.data)
+	}
+}
@@ -0,0 +1,115 @@
+package solver
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestSolve_Duals(t *testing.T) {
+	tests := []struct {
+		name         string
+		build        func() *model.LinearProgram
+		algorithms   []SolveOptions
+		duals        []float64
+		slacks       []float64
+		reducedCosts []float64
+	}{
+		{
+			// maximize 3x1 + 5x2 subject to x1 <= 4, 2x2 <= 12, 3x1 + 2x2 <= 18
+			name: "Maximize",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints: 3,
+					NbVariables:   2,
+					VariableNames: []string{"x1", "x2"},
+					Objective:     model.MAXIMIZE,
+					ObjCoeff:      []float64{3, 5},
+					Comparisons:   []model.Comparison{model.LE, model.LE, model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+						{1, 0},
+						{0, 2},
+						{3, 2},
+					}),
+					Rhs: []float64{4, 12, 18},
+				}
+			},
+			duals:        []float64{0, 1.5, 1},
+			slacks:       []float64{2, 0, 0},
+			reducedCosts: []float64{0, 0},
+		},
+		{
+			// minimize 2x + 3y subject to x + y >= 10, 2x + y <= 20, x - y = 2
+			name: "MinimizeMixedConstraints",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints: 3,
+					NbVariables:   2,
+					VariableNames: []string{"x", "y"},
+					Objective:     model.MINIMIZE,
+					ObjCoeff:      []float64{2, 3},
+					Comparisons:   []model.Comparison{model.BE, model.LE, model.EQ},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+						{1, 1},
+						{2, 1},
+						{1, -1},
+					}),
+					Rhs: []float64{10, 20, 2},
+				}
+			},
+			duals:        []float64{2.5, 0, -0.5},
+			slacks:       []float64{0, 4, 0},
+			reducedCosts: []float64{0, 0},
+		},
+		{
+			// minimize -x1 - 2x2 + x3 subject to x1 + x2 + x3 <= 4, x2 <= 3
+			name: "MinimizeWithBounds",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints:   1,
+					NbVariables:     3,
+					VariableNames:   []string{"x1", "x2", "x3"},
+					Objective:       model.MINIMIZE,
+					ObjCoeff:        []float64{-1, -2, 1},
+					Comparisons:     []model.Comparison{model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1, 1}}),
+					Rhs:             []float64{4},
+					UpperBounds:     []float64{math.Inf(1), 3, math.Inf(1)},
+				}
+			},
+			duals:        []float64{-1},
+			slacks:       []float64{0},
+			reducedCosts: []float64{0, -1, 2},
+		},
+	}
+
+	algorithms := map[string]SolveOptions{
+		"PrimalSimplex":  {Algorithm: PrimalSimplex},
+		"BigM":           {Algorithm: PrimalSimplex, Initialization: BigM},
+		"RevisedSimplex": {Algorithm: RevisedSimplex},
+		"InteriorPoint":  {Algorithm: InteriorPoint},
+	}
+
+	for _, tt := range tests {
+		for name, opts := range algorithms {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				lp := tt.build()
+				result, err := SolveWithOptions(context.Background(), lp, opts)
+				if err != nil {
+					t.Fatalf("SolveWithOptions() error = %v", err)
+				}
+				if !equalFloat64Slices(result.Duals, tt.duals, 1e-6) {
+					t.Errorf("Expected duals %v, got %v", tt.duals, result.Duals)
+				}
+				if !equalFloat64Slices(result.Slacks, tt.slacks, 1e-6) {
+					t.Errorf("Expected slacks %v, got %v", tt.slacks, result.Slacks)
+				}
+				if !equalFloat64Slices(result.ReducedCosts, tt.reducedCosts, 1e-6) {
+					t.Errorf("Expected reduced costs %v, got %v", tt.reducedCosts, result.ReducedCosts)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,433 @@
+package solver
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// ExactSolution holds an optimum computed in rational arithmetic.
+type ExactSolution struct {
+	VariableNames []string
+	Values        []*big.Rat // Value of each variable of the linear program
+	Objective     *big.Rat
+}
+
+// GetSolutionJSON returns the solution in JSON format, with every value written as an exact fraction such as "34/3".
+func (solution *ExactSolution) GetSolutionJSON() (string, error) {
+	values := make(map[string]string)
+	for j, value := range solution.Values {
+		values[solution.VariableNames[j]] = value.RatString()
+	}
+	values["objective"] = solution.Objective.RatString()
+
+	jsonBytes, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
+
+// Float64s returns the values followed by the objective value, rounded to float64 like LinearProgram.ObjVar.
+func (solution *ExactSolution) Float64s() []float64 {
+	values := make([]float64, len(solution.Values)+1)
+	for j, value := range solution.Values {
+		values[j], _ = value.Float64()
+	}
+	values[len(solution.Values)], _ = solution.Objective.Float64()
+	return values
+}
+
+// exactTerm is a column of the exact tableau that makes up part of an original variable.
+type exactTerm struct {
+	col  int
+	sign int64
+}
+
+// exactBound is the upper bound x' <= width of a shifted column.
+type exactBound struct {
+	col   int
+	width *big.Rat
+}
+
+// ExactSimplexTable is a simplex tableau in rational arithmetic. Pivots and ratio tests are exact,
+// so no tolerance is needed, and Bland's rule guarantees termination.
+type ExactSimplexTable struct {
+	data           [][]*big.Rat // (constraints + objective row) x (columns + RHS), the objective row holds -c
+	basicVariables []int
+	artificials    []bool // whether each column holds an artificial variable
+	initialBasis   []int  // slack or artificial column of each row in the first basis
+	negated        []bool // whether each row was negated for a non-negative RHS
+}
+
+// SolveExact solves the linear program with the two-phase simplex in rational arithmetic.
+// Every coefficient is read as the shortest decimal that rounds to it, so 0.1 is taken as 1/10.
+// The linear program is left unchanged.
+func SolveExact(lp *model.LinearProgram) (*ExactSolution, error) {
+	err := checkBounds(lp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Substitute every variable by non-negative columns: x = l + x', x = u - x' or x = x+ - x-
+	offsets := make([]*big.Rat, lp.NbVariables)
+	terms := make([][]exactTerm, lp.NbVariables)
+	var boundRows []exactBound
+	numCols := 0
+	for j := 0; j < lp.NbVariables; j++ {
+		lower, upper := lp.Bounds(j)
+		switch {
+		case !math.IsInf(lower, -1):
+			offsets[j] = ratFromFloat(lower)
+			terms[j] = []exactTerm{{col: numCols, sign: 1}}
+			if !math.IsInf(upper, 1) {
+				width := new(big.Rat).Sub(ratFromFloat(upper), offsets[j])
+				boundRows = append(boundRows, exactBound{col: numCols, width: width})
+			}
+			numCols++
+		case !math.IsInf(upper, 1):
+			offsets[j] = ratFromFloat(upper)
+			terms[j] = []exactTerm{{col: numCols, sign: -1}}
+			numCols++
+		default:
+			offsets[j] = new(big.Rat)
+			terms[j] = []exactTerm{{col: numCols, sign: 1}, {col: numCols + 1, sign: -1}}
+			numCols += 2
+		}
+	}
+
+	// Rows over the new columns, followed by their slack (+1), surplus (-1) or nothing for equalities
+	numRows := lp.NbConstraints + len(boundRows)
+	rows := make([][]*big.Rat, numRows)
+	rhs := make([]*big.Rat, numRows)
+	slackSigns := make([]int64, numRows)
+	for i := 0; i < lp.NbConstraints; i++ {
+		rows[i] = newRatSlice(numCols)
+		rhs[i] = ratFromFloat(lp.Rhs[i])
+		row := lp.ConstraintCoeff.Rows[i]
+		for k, j := range row.Indices {
+			a := ratFromFloat(row.Values[k])
+			for _, term := range terms[j] {
+				rows[i][term.col].Add(rows[i][term.col], new(big.Rat).Mul(a, big.NewRat(term.sign, 1)))
+			}
+			rhs[i].Sub(rhs[i], new(big.Rat).Mul(a, offsets[j]))
+		}
+		switch lp.Comparisons[i] {
+		case model.LE, model.LO:
+			slackSigns[i] = 1
+		case model.BE, model.BI:
+			slackSigns[i] = -1
+		}
+	}
+	for k, bound := range boundRows {
+		i := lp.NbConstraints + k
+		rows[i] = newRatSlice(numCols)
+		rows[i][bound.col].SetInt64(1)
+		rhs[i] = bound.width
+		slackSigns[i] = 1
+	}
+
+	// Objective to maximize over the new columns
+	sense := big.NewRat(1, 1)
+	if lp.Objective == model.MINIMIZE {
+		sense.SetInt64(-1)
+	}
+	cost := newRatSlice(numCols)
+	for j := 0; j < lp.NbVariables; j++ {
+		c := new(big.Rat).Mul(ratFromFloat(lp.ObjCoeff[j]), sense)
+		for _, term := range terms[j] {
+			cost[term.col].Add(cost[term.col], new(big.Rat).Mul(c, big.NewRat(term.sign, 1)))
+		}
+	}
+
+	var table ExactSimplexTable
+	table.initialize(rows, rhs, slackSigns, cost)
+
+	err = table.phaseOne()
+	var infeasible *InfeasibleError
+	if errors.As(err, &infeasible) {
+		infeasible.Farkas = infeasible.Farkas[:lp.NbConstraints] // The bound rows are covered by the bounds
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = table.optimize()
+	var unbounded *UnboundedError
+	if errors.As(err, &unbounded) {
+		ray := make([]float64, lp.NbVariables)
+		for j := range ray {
+			for _, term := range terms[j] {
+				ray[j] += float64(term.sign) * unbounded.Ray[term.col]
+			}
+		}
+		unbounded.Ray = ray
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Map the columns back to the original variables
+	columnValues := table.columnValues()
+	solution := &ExactSolution{Objective: ratFromFloat(lp.ObjConstant)}
+	for j := 0; j < lp.NbVariables; j++ {
+		value := new(big.Rat).Set(offsets[j])
+		for _, term := range terms[j] {
+			value.Add(value, new(big.Rat).Mul(columnValues[term.col], big.NewRat(term.sign, 1)))
+		}
+		solution.VariableNames = append(solution.VariableNames, lp.VariableName(j))
+		solution.Values = append(solution.Values, value)
+		solution.Objective.Add(solution.Objective, new(big.Rat).Mul(ratFromFloat(lp.ObjCoeff[j]), value))
+	}
+
+	return solution, nil
+}
+
+// initialize builds the tableau of rows*x + slackSigns[i]*s_i = rhs maximizing cost*x. Rows with a negative RHS
+// are negated, and rows whose slack cannot start in the basis get an artificial variable.
+// The objective row is left for phaseOne.
+func (table *ExactSimplexTable) initialize(rows [][]*big.Rat, rhs []*big.Rat, slackSigns []int64, cost []*big.Rat) {
+	numRows := len(rows)
+	numCols := len(cost)
+
+	var slackCols []int
+	numSlacks := 0
+	for _, sign := range slackSigns {
+		slackCols = append(slackCols, numCols+numSlacks)
+		if sign != 0 {
+			numSlacks++
+		}
+	}
+
+	// Find the rows that need an artificial variable
+	negate := make([]bool, numRows)
+	var artificialRows []int
+	for i := range rows {
+		negate[i] = rhs[i].Sign() < 0
+		sign := slackSigns[i]
+		if negate[i] {
+			sign = -sign
+		}
+		if sign != 1 {
+			artificialRows = append(artificialRows, i)
+		}
+	}
+
+	width := numCols + numSlacks + len(artificialRows) + 1
+	table.data = make([][]*big.Rat, numRows+1)
+	table.basicVariables = make([]int, numRows)
+	table.artificials = make([]bool, width-1)
+	table.initialBasis = make([]int, numRows)
+	table.negated = negate
+	for i := range rows {
+		table.data[i] = newRatSlice(width)
+		for j, value := range rows[i] {
+			table.data[i][j].Set(value)
+		}
+		if slackSigns[i] != 0 {
+			table.data[i][slackCols[i]].SetInt64(slackSigns[i])
+		}
+		table.data[i][width-1].Set(rhs[i])
+		if negate[i] {
+			for _, value := range table.data[i] {
+				value.Neg(value)
+			}
+		}
+		table.basicVariables[i] = slackCols[i]
+		table.initialBasis[i] = slackCols[i]
+	}
+	for k, i := range artificialRows {
+		col := numCols + numSlacks + k
+		table.data[i][col].SetInt64(1)
+		table.basicVariables[i] = col
+		table.initialBasis[i] = col
+		table.artificials[col] = true
+	}
+
+	// Keep the original objective in the last row, phaseOne swaps it out while it runs
+	table.data[numRows] = newRatSlice(width)
+	for j, c := range cost {
+		table.data[numRows][j].Neg(c)
+	}
+}
+
+// phaseOne drives the artificial variables to zero, or returns an *InfeasibleError whose Farkas certificate
+// has one multiplier per row of the tableau.
+func (table *ExactSimplexTable) phaseOne() error {
+	objectiveRow := len(table.data) - 1
+	rhsCol := len(table.data[0]) - 1
+
+	phaseTwoObjective := table.data[objectiveRow]
+	table.data[objectiveRow] = newRatSlice(rhsCol + 1)
+	for j, artificial := range table.artificials {
+		if artificial {
+			table.data[objectiveRow][j].SetInt64(1) // Maximize -(sum of the artificial variables)
+		}
+	}
+	table.priceOut()
+
+	err := table.optimize()
+	if err != nil {
+		return err
+	}
+	if table.data[objectiveRow][rhsCol].Sign() < 0 {
+		return &InfeasibleError{Farkas: table.farkas()}
+	}
+
+	// Pivot the artificial variables left at zero out of the basis, redundant rows keep theirs
+	for i, basic := range table.basicVariables {
+		if !table.artificials[basic] {
+			continue
+		}
+		for j := 0; j < rhsCol; j++ {
+			if !table.artificials[j] && table.data[i][j].Sign() != 0 {
+				table.pivot(i, j)
+				break
+			}
+		}
+	}
+
+	table.data[objectiveRow] = phaseTwoObjective
+	table.priceOut()
+	return nil
+}
+
+// optimize runs simplex iterations with Bland's rule until the objective row is optimal, or returns an
+// *UnboundedError whose ray is over the columns of the tableau. Artificial variables never re-enter the basis.
+func (table *ExactSimplexTable) optimize() error {
+	objectiveRow := len(table.data) - 1
+	rhsCol := len(table.data[0]) - 1
+
+	for {
+		pivotCol := -1
+		for j := 0; j < rhsCol; j++ {
+			if !table.artificials[j] && table.data[objectiveRow][j].Sign() < 0 {
+				pivotCol = j
+				break
+			}
+		}
+		if pivotCol == -1 {
+			return nil
+		}
+
+		// Minimum ratio test, ties go to the basic variable with the smallest index
+		pivotRow := -1
+		var smallestRatio *big.Rat
+		for i := 0; i < objectiveRow; i++ {
+			if table.data[i][pivotCol].Sign() <= 0 {
+				continue
+			}
+			ratio := new(big.Rat).Quo(table.data[i][rhsCol], table.data[i][pivotCol])
+			if pivotRow == -1 {
+				pivotRow, smallestRatio = i, ratio
+				continue
+			}
+			cmp := ratio.Cmp(smallestRatio)
+			if cmp < 0 || (cmp == 0 && table.basicVariables[i] < table.basicVariables[pivotRow]) {
+				pivotRow, smallestRatio = i, ratio
+			}
+		}
+		if pivotRow == -1 {
+			return &UnboundedError{Ray: table.ray(pivotCol)}
+		}
+
+		table.pivot(pivotRow, pivotCol)
+	}
+}
+
+// pivot makes pivotCol basic in pivotRow.
+func (table *ExactSimplexTable) pivot(pivotRow, pivotCol int) {
+	pivotElement := new(big.Rat).Set(table.data[pivotRow][pivotCol])
+	for _, value := range table.data[pivotRow] {
+		value.Quo(value, pivotElement)
+	}
+
+	product := new(big.Rat)
+	for i, row := range table.data {
+		if i == pivotRow || row[pivotCol].Sign() == 0 {
+			continue
+		}
+		factor := new(big.Rat).Set(row[pivotCol])
+		for j, value := range row {
+			value.Sub(value, product.Mul(factor, table.data[pivotRow][j]))
+		}
+	}
+
+	table.basicVariables[pivotRow] = pivotCol
+}
+
+// farkas returns the Phase I duals at an optimum with positive artificial variables, signed for the rows as
+// stated. The objective row holds its first value minus y*A for the rows as built, and the first basis has a unit
+// column in every row, so y_i can be read from it. Optimality makes y*A <= 0 over the columns other than the
+// artificials and y*rhs > 0, so -y is the certificate.
+func (table *ExactSimplexTable) farkas() []float64 {
+	objectiveRow := len(table.data) - 1
+	farkas := make([]float64, len(table.initialBasis))
+	for i, col := range table.initialBasis {
+		y := new(big.Rat).Neg(table.data[objectiveRow][col])
+		if table.artificials[col] {
+			y.Add(y, big.NewRat(1, 1))
+		}
+		if !table.negated[i] {
+			y.Neg(y)
+		}
+		farkas[i], _ = y.Float64()
+	}
+	return farkas
+}
+
+// ray returns the direction over the columns along which the entering column pivotCol grows without limit.
+func (table *ExactSimplexTable) ray(pivotCol int) []float64 {
+	ray := make([]float64, len(table.data[0])-1)
+	ray[pivotCol] = 1
+	for i, basic := range table.basicVariables {
+		value, _ := new(big.Rat).Neg(table.data[i][pivotCol]).Float64()
+		ray[basic] = value
+	}
+	return ray
+}
+
+// priceOut eliminates the basic variables from the objective row.
+func (table *ExactSimplexTable) priceOut() {
+	objectiveRow := len(table.data) - 1
+	product := new(big.Rat)
+	for i, basic := range table.basicVariables {
+		factor := new(big.Rat).Set(table.data[objectiveRow][basic])
+		if factor.Sign() == 0 {
+			continue
+		}
+		for j, value := range table.data[objectiveRow] {
+			value.Sub(value, product.Mul(factor, table.data[i][j]))
+		}
+	}
+}
+
+// columnValues returns the value of every column in the current basic solution.
+func (table *ExactSimplexTable) columnValues() []*big.Rat {
+	rhsCol := len(table.data[0]) - 1
+	values := newRatSlice(rhsCol)
+	for i, basic := range table.basicVariables {
+		values[basic].Set(table.data[i][rhsCol])
+	}
+	return values
+}
+
+// ratFromFloat returns the shortest decimal that rounds to v as a fraction, e.g. 1/10 for 0.1.
+func ratFromFloat(v float64) *big.Rat {
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(v, 'g', -1, 64))
+	if !ok {
+		return new(big.Rat).SetFloat64(v)
+	}
+	return r
+}
+
+func newRatSlice(n int) []*big.Rat {
+	values := make([]*big.Rat, n)
+	for j := range values {
+		values[j] = new(big.Rat)
+	}
+	return values
+}
@@ -0,0 +1,180 @@
+package solver
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestSolveExact(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints: 2,
+		NbVariables:   2,
+		VariableNames: []string{"x1", "x2"},
+		Objective:     model.MAXIMIZE,
+		ObjCoeff:      []float64{1, 1},
+		Comparisons:   []model.Comparison{model.LE, model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{3, 0},
+			{0, 3},
+		}),
+		Rhs: []float64{10, 24},
+	}
+
+	solution, err := SolveExact(lp)
+	if err != nil {
+		t.Fatalf("SolveExact() error = %v", err)
+	}
+
+	jsonString, err := solution.GetSolutionJSON()
+	if err != nil {
+		t.Fatalf("GetSolutionJSON() error = %v", err)
+	}
+	expected := `{"objective":"34/3","x1":"10/3","x2":"8"}`
+	if jsonString != expected {
+		t.Errorf("Expected %s, got %s", expected, jsonString)
+	}
+
+	if lp.State != model.Undefined || lp.NbVariables != 2 || lp.ObjVar != nil {
+		t.Errorf("Expected SolveExact to leave the linear program unchanged")
+	}
+}
+
+func TestSolveExact_MixedConstraints(t *testing.T) {
+	// minimize 0.1x + 0.3y + z subject to x + y >= 1, x - z = 0.2, y <= 2, z free
+	lp := &model.LinearProgram{
+		NbConstraints: 2,
+		NbVariables:   3,
+		VariableNames: []string{"x", "y", "z"},
+		Objective:     model.MINIMIZE,
+		ObjCoeff:      []float64{0.1, 0.3, 1},
+		Comparisons:   []model.Comparison{model.BE, model.EQ},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 1, 0},
+			{1, 0, -1},
+		}),
+		Rhs:         []float64{1, 0.2},
+		Signs:       []model.Sign{model.NonNegative, model.NonNegative, model.Free},
+		UpperBounds: []float64{math.Inf(1), 2, math.Inf(1)},
+	}
+
+	solution, err := SolveExact(lp)
+	if err != nil {
+		t.Fatalf("SolveExact() error = %v", err)
+	}
+
+	expected := []string{"0", "1", "-1/5"}
+	for j, value := range solution.Values {
+		if value.RatString() != expected[j] {
+			t.Errorf("Expected %s = %s, got %s", solution.VariableNames[j], expected[j], value.RatString())
+		}
+	}
+	if solution.Objective.RatString() != "1/10" {
+		t.Errorf("Expected objective 1/10, got %s", solution.Objective.RatString())
+	}
+}
+
+func TestSolveExact_Errors(t *testing.T) {
+	t.Run("Infeasible", func(t *testing.T) {
+		lp := &model.LinearProgram{
+			NbConstraints:   2,
+			NbVariables:     2,
+			Objective:       model.MAXIMIZE,
+			ObjCoeff:        []float64{1, 1},
+			Comparisons:     []model.Comparison{model.LE, model.BE},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}, {1, 1}}),
+			Rhs:             []float64{1, 2},
+		}
+		_, err := SolveExact(lp)
+		if !errors.Is(err, ErrInfeasible) || err.Error() != "infeasible problem" {
+			t.Errorf("Expected error to be 'infeasible problem', got %v", err)
+		}
+		if err := CheckCertificate(lp, err); err != nil {
+			t.Errorf("CheckCertificate() error = %v", err)
+		}
+	})
+
+	t.Run("InfeasibleWithinBounds", func(t *testing.T) {
+		// x + y >= 3 with x <= 1 and 1 <= y <= 1.5
+		lp := &model.LinearProgram{
+			NbConstraints:   1,
+			NbVariables:     2,
+			Objective:       model.MINIMIZE,
+			ObjCoeff:        []float64{1, 1},
+			Comparisons:     []model.Comparison{model.BE},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}}),
+			Rhs:             []float64{3},
+			LowerBounds:     []float64{0, 1},
+			UpperBounds:     []float64{1, 1.5},
+		}
+		_, err := SolveExact(lp)
+		if !errors.Is(err, ErrInfeasible) {
+			t.Errorf("Expected an infeasible problem, got %v", err)
+		}
+		if err := CheckCertificate(lp, err); err != nil {
+			t.Errorf("CheckCertificate() error = %v", err)
+		}
+	})
+
+	t.Run("Unbounded", func(t *testing.T) {
+		lp := &model.LinearProgram{
+			NbConstraints:   1,
+			NbVariables:     2,
+			Objective:       model.MAXIMIZE,
+			ObjCoeff:        []float64{1, 1},
+			Comparisons:     []model.Comparison{model.LE},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, -1}}),
+			Rhs:             []float64{1},
+		}
+		_, err := SolveExact(lp)
+		if !errors.Is(err, ErrUnbounded) || err.Error() != "unbounded problem" {
+			t.Errorf("Expected error to be 'unbounded problem', got %v", err)
+		}
+		if err := CheckCertificate(lp, err); err != nil {
+			t.Errorf("CheckCertificate() error = %v", err)
+		}
+	})
+
+	t.Run("UnboundedFreeVariable", func(t *testing.T) {
+		// minimize x - y subject to x + y = 1, x <= 4, y free
+		lp := &model.LinearProgram{
+			NbConstraints:   1,
+			NbVariables:     2,
+			Objective:       model.MINIMIZE,
+			ObjCoeff:        []float64{1, -1},
+			Comparisons:     []model.Comparison{model.EQ},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}}),
+			Rhs:             []float64{1},
+			Signs:           []model.Sign{model.Free, model.Free},
+			UpperBounds:     []float64{4, math.Inf(1)},
+		}
+		_, err := SolveExact(lp)
+		if !errors.Is(err, ErrUnbounded) {
+			t.Errorf("Expected an unbounded problem, got %v", err)
+		}
+		if err := CheckCertificate(lp, err); err != nil {
+			t.Errorf("CheckCertificate() error = %v", err)
+		}
+	})
+}
+
+func TestSolveExact_MatchesFloatSolver(t *testing.T) {
+	exact, err := SolveExact(randomPricingProblem(5, 8, 12))
+	if err != nil {
+		t.Fatalf("SolveExact() error = %v", err)
+	}
+
+	lp := randomPricingProblem(5, 8, 12)
+	result, err := Solve(lp)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+
+	values := exact.Float64s()
+	objective := len(values) - 1
+	if math.Abs(values[objective]-result.ObjVar[objective]) > 1e-9 {
+		t.Errorf("Expected exact objective %v to match the float objective %v", values[objective], result.ObjVar[objective])
+	}
+}
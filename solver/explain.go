@@ -0,0 +1,190 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// Explain returns the worked solution of the trace: the explanation of every step, numbered by iteration, followed
+// by the summary.
+func (trace *Trace) Explain() string {
+	var builder strings.Builder
+	for _, step := range trace.Steps {
+		builder.WriteString(fmt.Sprintf("%d. %s: %s\n", step.Iteration, step.Phase, step.Explanation))
+	}
+	if trace.Summary != "" {
+		builder.WriteString(trace.Summary + "\n")
+	}
+	return builder.String()
+}
+
+// explainPrimal explains the choices of a step of the primal simplex. upper is the distance of the entering
+// variable to its other bound and fallback tells that Bland's rule replaced the pricing rule after degenerate pivots.
+func (step TraceStep) explainPrimal(upper float64, fallback bool) string {
+	var rule string
+	switch step.Rule {
+	case DantzigPricing.String():
+		rule = "the most negative one"
+	case DevexPricing.String():
+		rule = "the most negative one relative to the Devex estimate of its edge length"
+	case SteepestEdgePricing.String():
+		rule = "the most negative one relative to the length of its edge"
+	case PartialPricing.String():
+		rule = "the most negative one among the candidates of partial pricing"
+	default:
+		rule = "the first negative one"
+		if fallback {
+			rule += fmt.Sprintf(" (Bland's rule, after %d degenerate pivots in a row)", DegeneratePivotLimit)
+		}
+	}
+	entering := fmt.Sprintf("%s enters because its reduced cost %s is %s", step.Entering, formatNumber(step.ReducedCost), rule)
+
+	if step.BoundFlip {
+		return fmt.Sprintf("%s; it reaches its other bound, %s away, before any basic variable reaches a bound, "+
+			"so it moves there without a pivot.", entering, formatNumber(upper))
+	}
+	if step.Leaving == "" {
+		return entering + "; no basic variable reaches a bound as it increases, so the objective improves without limit."
+	}
+
+	var leaving TraceRatio
+	var tied []string
+	for _, r := range step.Ratios {
+		if r.Variable == step.Leaving {
+			leaving = r
+		}
+	}
+	for _, r := range step.Ratios {
+		if r.Variable != step.Leaving && math.Abs(r.Ratio-leaving.Ratio) <= 1e-10 {
+			tied = append(tied, r.Variable)
+		}
+	}
+
+	bound := ""
+	if step.PivotElement < 0 {
+		bound = " at its upper bound"
+	}
+	smallest := "the smallest"
+	switch {
+	case len(step.Ratios) == 1:
+		smallest = "the only one"
+	case len(tied) > 0:
+		smallest += " (tied with " + strings.Join(tied, ", ") + "; the first row wins)"
+	}
+	explanation := fmt.Sprintf("%s; %s leaves%s with ratio %s = %s/%s, %s.", entering, step.Leaving, bound,
+		formatNumber(leaving.Ratio), formatNumber(leaving.Numerator), formatNumber(leaving.Denominator), smallest)
+	if math.Abs(leaving.Ratio) <= 1e-10 {
+		explanation += " The pivot is degenerate: the objective does not change."
+	}
+	return explanation
+}
+
+// explainDual explains the choices of a step of the dual simplex, whose leaving variable violates its bound by
+// violation.
+func (step TraceStep) explainDual(violation float64) string {
+	leaving := fmt.Sprintf("%s leaves because it violates its bound by %s, the most of the basic variables",
+		step.Leaving, formatNumber(violation))
+	if step.Entering == "" {
+		return leaving + "; no entry of its row is negative, so no variable can enter to repair it."
+	}
+
+	var entering TraceRatio
+	for _, r := range step.Ratios {
+		if r.Variable == step.Entering {
+			entering = r
+		}
+	}
+	smallest := "the smallest |reduced cost / entry| of the negative entries of its row"
+	if len(step.Ratios) == 1 {
+		smallest = "the only negative entry of its row"
+	}
+	return fmt.Sprintf("%s; %s enters with ratio %s = %s/%s, %s, which keeps every reduced cost non-negative.",
+		leaving, step.Entering, formatNumber(entering.Ratio), formatNumber(entering.Numerator),
+		formatNumber(entering.Denominator), smallest)
+}
+
+// explainDriveOut explains the pivot that drives an artificial variable out of the basis after Phase I.
+func (step TraceStep) explainDriveOut() string {
+	return fmt.Sprintf("%s is still basic at zero after Phase I; %s replaces it on the nonzero entry %s, "+
+		"a degenerate pivot that leaves the solution unchanged.", step.Leaving, step.Entering,
+		formatNumber(step.PivotElement))
+}
+
+// summarize returns why the solve of lp ended with the status of the result: the final tableau is optimal, proves
+// that the problem is infeasible or unbounded, or a limit stopped the iterations.
+func (trace *Trace) summarize(lp *model.LinearProgram, result *Result) string {
+	var last *TraceStep
+	if len(trace.Steps) > 0 {
+		last = &trace.Steps[len(trace.Steps)-1]
+	}
+
+	switch result.Status {
+	case Optimal:
+		summary := "The solution is optimal."
+		if trace.Final != nil {
+			summary = "The tableau is optimal: no reduced cost in the objective row is negative, " +
+				"so no variable can enter and improve the objective."
+		}
+		values := make([]string, lp.NbVariables)
+		for j := range values {
+			name := lp.VariableName(j)
+			values[j] = fmt.Sprintf("%s = %s", name, formatNumber(result.Values[name]))
+		}
+		summary += fmt.Sprintf(" The optimum is %s with %s.", formatNumber(result.Objective), strings.Join(values, ", "))
+		if result.AlternativeOptima {
+			summary += " A nonbasic variable has a zero reduced cost, so other solutions reach the same optimum."
+		}
+		return summary
+
+	case Infeasible:
+		if last != nil && last.Phase == TraceDualSimplex && last.Entering == "" {
+			return fmt.Sprintf("The problem is infeasible: the row of %s needs its value to increase, but none of "+
+				"its entries is negative, so no variables within their bounds can satisfy it.", last.Leaving)
+		}
+		if trace.Final != nil { // Phase I and Big-M end on the Phase I objective
+			rhs := trace.Final.Rows[len(trace.Final.Rows)-1]
+			return fmt.Sprintf("The problem is infeasible: Phase I ended with the artificial variables summing to "+
+				"%s instead of 0, so no point satisfies every constraint.", formatNumber(-rhs[len(rhs)-1]))
+		}
+		return "The problem is infeasible: its constraints and bounds contradict each other."
+
+	case Unbounded:
+		if last != nil && last.Entering != "" && last.Leaving == "" && !last.BoundFlip {
+			return fmt.Sprintf("The problem is unbounded: %s can increase without limit, since no basic variable "+
+				"reaches a bound along its column, and the objective improves with it.", last.Entering)
+		}
+		return "The problem is unbounded: the objective improves without limit."
+
+	case IterationLimit:
+		return fmt.Sprintf("The solve stopped at the iteration limit after %s, before an optimum was found.",
+			countIterations(result.Iterations))
+	case TimeLimit:
+		return fmt.Sprintf("The solve stopped at the time limit after %s, before an optimum was found.",
+			countIterations(result.Iterations))
+	case Canceled:
+		return fmt.Sprintf("The solve was canceled after %s, before an optimum was found.",
+			countIterations(result.Iterations))
+	}
+	return ""
+}
+
+// countIterations writes the number of iterations with the noun.
+func countIterations(n int) string {
+	if n == 1 {
+		return "1 iteration"
+	}
+	return fmt.Sprintf("%d iterations", n)
+}
+
+// formatNumber writes the value rounded to 6 decimals, without trailing zeros.
+func formatNumber(value float64) string {
+	rounded := math.Round(value*1e6) / 1e6
+	if rounded == 0 { // -0 is written as 0
+		rounded = 0
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
@@ -0,0 +1,157 @@
+package solver
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestTrace_Explain(t *testing.T) {
+	phaseOne := parametricProblem()
+	phaseOne.Comparisons[1] = model.BE
+
+	infeasible := parametricProblem()
+	infeasible.Comparisons[2] = model.BE
+	infeasible.Rhs[2] = 40
+
+	unbounded := parametricProblem()
+	unbounded.Comparisons[1], unbounded.Comparisons[2] = model.BE, model.BE
+
+	// min x + y s.t. x + y >= 4, x + y <= 2
+	dualInfeasible := &model.LinearProgram{
+		NbConstraints:   2,
+		NbVariables:     2,
+		VariableNames:   []string{"x", "y"},
+		Objective:       model.MINIMIZE,
+		ObjCoeff:        []float64{1, 1},
+		Comparisons:     []model.Comparison{model.BE, model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}, {1, 1}}),
+		Rhs:             []float64{4, 2},
+	}
+
+	// max x + y s.t. x + y <= 4, x <= 1
+	boundFlip := &model.LinearProgram{
+		NbConstraints:   1,
+		NbVariables:     2,
+		VariableNames:   []string{"x", "y"},
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        []float64{1, 1},
+		Comparisons:     []model.Comparison{model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}}),
+		Rhs:             []float64{4},
+		UpperBounds:     []float64{1, math.Inf(1)},
+	}
+
+	// x + y = 2 twice leaves an artificial variable basic at zero
+	redundant := &model.LinearProgram{
+		NbConstraints:   2,
+		NbVariables:     2,
+		VariableNames:   []string{"x", "y"},
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        []float64{1, 2},
+		Comparisons:     []model.Comparison{model.EQ, model.EQ},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}, {2, 2}}),
+		Rhs:             []float64{2, 4},
+	}
+
+	tests := []struct {
+		name        string
+		lp          *model.LinearProgram
+		opts        SolveOptions
+		explanation string
+		summary     string
+	}{
+		{
+			"Dantzig", parametricProblem(), SolveOptions{Pricing: DantzigPricing},
+			"1. Phase II: y enters because its reduced cost -5 is the most negative one; s2 leaves with ratio 6 = 12/2, the smallest.",
+			"The tableau is optimal: no reduced cost in the objective row is negative, so no variable can enter and improve the objective. The optimum is 36 with x = 2, y = 6.",
+		},
+		{
+			"PhaseOne", phaseOne, SolveOptions{},
+			"3. Phase II: s2 enters because its reduced cost -1.5 is the first negative one; x leaves with ratio 6 = 2/0.333333, the only one.",
+			"The tableau is optimal: no reduced cost in the objective row is negative, so no variable can enter and improve the objective. The optimum is 45 with x = 0, y = 9.",
+		},
+		{
+			"BoundFlip", boundFlip, SolveOptions{},
+			"1. Phase II: x enters because its reduced cost -1 is the first negative one; it reaches its other bound, 1 away, before any basic variable reaches a bound, so it moves there without a pivot.",
+			"The tableau is optimal: no reduced cost in the objective row is negative, so no variable can enter and improve the objective. The optimum is 4 with x = 1, y = 3. A nonbasic variable has a zero reduced cost, so other solutions reach the same optimum.",
+		},
+		{
+			"DriveOut", redundant, SolveOptions{},
+			"2. Phase I: a1 is still basic at zero after Phase I; s1 replaces it on the nonzero entry -1, a degenerate pivot that leaves the solution unchanged.",
+			"The tableau is optimal: no reduced cost in the objective row is negative, so no variable can enter and improve the objective. The optimum is 4 with x = 0, y = 2.",
+		},
+		{
+			"Infeasible", infeasible, SolveOptions{Initialization: BigM},
+			"2. Big-M: y enters because its reduced cost -2000005 is the first negative one; s2 leaves with ratio 6 = 12/2, the smallest.",
+			"The problem is infeasible: Phase I ended with the artificial variables summing to 16 instead of 0, so no point satisfies every constraint.",
+		},
+		{
+			"DualInfeasible", dualInfeasible, SolveOptions{Algorithm: DualSimplex},
+			"2. Dual simplex: s2 leaves because it violates its bound by 2, the most of the basic variables; no entry of its row is negative, so no variable can enter to repair it.",
+			"The problem is infeasible: the row of s2 needs its value to increase, but none of its entries is negative, so no variables within their bounds can satisfy it.",
+		},
+		{
+			"Unbounded", unbounded, SolveOptions{},
+			"5. Phase II: s3 enters because its reduced cost -2.5 is the first negative one; no basic variable reaches a bound as it increases, so the objective improves without limit.",
+			"The problem is unbounded: s3 can increase without limit, since no basic variable reaches a bound along its column, and the objective improves with it.",
+		},
+		{
+			"IterationLimit", parametricProblem(), SolveOptions{MaxIterations: 1},
+			"1. Phase II: x enters because its reduced cost -3 is the first negative one; s1 leaves with ratio 4 = 4/1, the smallest.",
+			"The solve stopped at the iteration limit after 1 iteration, before an optimum was found.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Trace = true
+			result, _ := SolveWithOptions(context.Background(), tt.lp, opts)
+			if result == nil || result.Trace == nil {
+				t.Fatalf("Expected a trace, got %+v", result)
+			}
+			if result.Trace.Summary != tt.summary {
+				t.Errorf("Expected the summary\n%s\ngot\n%s", tt.summary, result.Trace.Summary)
+			}
+			explained := result.Trace.Explain()
+			if !strings.Contains(explained, tt.explanation+"\n") || !strings.HasSuffix(explained, tt.summary+"\n") {
+				t.Errorf("Expected the explanation %q and the summary, got:\n%s", tt.explanation, explained)
+			}
+			if text := result.Trace.String(); !strings.HasSuffix(text, tt.summary+"\n") {
+				t.Errorf("Expected the trace to end with the summary, got:\n%s", text)
+			}
+		})
+	}
+}
+
+func TestTraceStep_ExplainPrimal(t *testing.T) {
+	step := TraceStep{
+		Entering:     "x",
+		Leaving:      "s1",
+		PivotElement: 2,
+		ReducedCost:  -4,
+		Rule:         BlandPricing.String(),
+		Ratios: []TraceRatio{
+			{Variable: "s1", Numerator: 0, Denominator: 2, Ratio: 0},
+			{Variable: "s2", Numerator: 0, Denominator: 1, Ratio: 0},
+		},
+	}
+	expected := "x enters because its reduced cost -4 is the first negative one (Bland's rule, after 50 degenerate pivots in a row); " +
+		"s1 leaves with ratio 0 = 0/2, the smallest (tied with s2; the first row wins). The pivot is degenerate: the objective does not change."
+	if got := step.explainPrimal(math.Inf(1), true); got != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+	}
+
+	// A basic variable that decreases from its upper bound gives a negative pivot element
+	step.Rule, step.PivotElement = SteepestEdgePricing.String(), -2
+	step.Ratios = []TraceRatio{{Variable: "s1", Numerator: 3, Denominator: 2, Ratio: 1.5}}
+	expected = "x enters because its reduced cost -4 is the most negative one relative to the length of its edge; " +
+		"s1 leaves at its upper bound with ratio 1.5 = 3/2, the only one."
+	if got := step.explainPrimal(math.Inf(1), false); got != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+	}
+}
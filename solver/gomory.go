@@ -0,0 +1,287 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// DefaultCutRounds is the number of rounds of cuts added by SolveCuttingPlanes when
+// CuttingPlaneOptions.MaxRounds is not set.
+const DefaultCutRounds = 50
+
+// Cut is the inequality Coeffs*x >= Rhs over the original variables, which cuts off the optimum of the LP
+// relaxation while every integer solution satisfies it.
+type Cut struct {
+	Coeffs []float64
+	Rhs    float64
+}
+
+// String returns the cut as a constraint, such as "x + 2*y >= 3".
+func (cut Cut) String(lp *model.LinearProgram) string {
+	var sb strings.Builder
+	for j, coeff := range cut.Coeffs {
+		if coeff == 0 {
+			continue
+		}
+		switch {
+		case sb.Len() == 0 && coeff < 0:
+			sb.WriteString("-")
+		case sb.Len() > 0 && coeff < 0:
+			sb.WriteString(" - ")
+		case sb.Len() > 0:
+			sb.WriteString(" + ")
+		}
+		if math.Abs(coeff) != 1 {
+			fmt.Fprintf(&sb, "%g*", math.Abs(coeff))
+		}
+		sb.WriteString(lp.VariableName(j))
+	}
+	if sb.Len() == 0 {
+		sb.WriteString("0")
+	}
+	fmt.Fprintf(&sb, " >= %g", cut.Rhs)
+	return sb.String()
+}
+
+// CuttingPlaneOptions configures a call to SolveCuttingPlanes.
+type CuttingPlaneOptions struct {
+	LP                   SolveOptions // Options of the LP solves, which always run on the tableau
+	MaxRounds            int          // Maximum number of rounds of cuts, 0 means DefaultCutRounds
+	IntegralityTolerance float64      // Distance to the nearest integer under which a value counts as integer
+}
+
+// CuttingPlaneStats reports the outcome of the cutting-plane method.
+type CuttingPlaneStats struct {
+	Rounds  int  // Rounds of cuts added to the problem
+	Cuts    int  // Cuts added over all rounds
+	Integer bool // Whether the final LP optimum is integer, and so optimal for the integer problem
+}
+
+// GomoryCuts returns the Gomory mixed-integer cuts of the optimal tableau, one for every row whose basic
+// variable is an integer variable with a fractional value. The tableau row x_B + sum a_k*x_k = b over the
+// nonbasic columns gives sum g_k*x_k >= 1, where g_k depends on the fractional parts of a_k and b, and the cut
+// is mapped back to the original variables through the conversions of the problem, which must be in slack form.
+// An integer variable only counts as integer in the tableau when its bounds are integer, see SolveCuttingPlanes.
+// Rows that need the parts of a split free variable on their own give no cut.
+func (table *SimplexTable) GomoryCuts(lp *model.LinearProgram, tolerance float64) []Cut {
+	rhsCol := len(table.data[0]) - 1
+	var cuts []Cut
+	for i, col := range table.basicVariables {
+		if !table.integerColumn(lp, int(col)) {
+			continue
+		}
+		f0 := table.data[i][rhsCol] - math.Floor(table.data[i][rhsCol])
+		if f0 <= tolerance || f0 >= 1-tolerance {
+			continue
+		}
+		if cut := table.gomoryCut(lp, i, f0); cut != nil {
+			cuts = append(cuts, *cut)
+		}
+	}
+	return cuts
+}
+
+// integerColumn reports whether the column of the tableau only takes integer values: it is an original integer
+// variable, not split, whose offset and upper bound are integer, so that shifting and complementing keep it integer.
+func (table *SimplexTable) integerColumn(lp *model.LinearProgram, col int) bool {
+	numOrigVars := lp.NbVariables - len(lp.SplitVariables) - len(lp.SlackVariablesNames)
+	if col >= numOrigVars || !lp.IsInteger(col) {
+		return false
+	}
+	for _, j := range lp.SplitVariables {
+		if j == col {
+			return false
+		}
+	}
+	if col < len(lp.VariableOffsets) && lp.VariableOffsets[col] != math.Trunc(lp.VariableOffsets[col]) {
+		return false
+	}
+	upper := table.upperBound(col)
+	return math.IsInf(upper, 1) || upper == math.Trunc(upper)
+}
+
+// gomoryCut derives the cut of a tableau row whose basic variable has the fractional part f0 and maps it to the
+// original variables. It returns nil if the cut cannot be written over the original variables.
+func (table *SimplexTable) gomoryCut(lp *model.LinearProgram, row int, f0 float64) *Cut {
+	epsilon := 1e-12
+	numOrigVars := lp.NbVariables - len(lp.SplitVariables) - len(lp.SlackVariablesNames)
+	firstSlack := table.firstSlack()
+
+	split := make([]bool, numOrigVars)
+	for _, j := range lp.SplitVariables {
+		split[j] = true
+	}
+	basic := make([]bool, len(table.data[0])-1)
+	for _, col := range table.basicVariables {
+		basic[int(col)] = true
+	}
+
+	// The tableau cut is sum g_k*x'_k >= 1, where every column x'_k is an affine function of the original
+	// variables x. The cut is accumulated as coeffs*x + constant >= 1.
+	coeffs := make([]float64, numOrigVars)
+	constant := 0.0
+	// column adds g times c_j = m_j*x_j - o_j, the shifted and mirrored variable j
+	column := func(j int, g float64) {
+		sign := 1.0
+		if lp.MirroredVariables != nil && lp.MirroredVariables[j] {
+			sign = -1
+		}
+		coeffs[j] += g * sign
+		if j < len(lp.VariableOffsets) {
+			constant -= g * lp.VariableOffsets[j]
+		}
+	}
+
+	// Artificial columns are zero in every solution of the problem and drop out of the cut
+	for k := 0; k < firstSlack+len(table.basicVariables); k++ {
+		a := table.data[row][k]
+		if basic[k] || math.Abs(a) <= epsilon {
+			continue
+		}
+
+		var g float64
+		if table.integerColumn(lp, k) {
+			if fk := a - math.Floor(a); fk <= f0 {
+				g = fk / f0
+			} else {
+				g = (1 - fk) / (1 - f0)
+			}
+		} else if a > 0 {
+			g = a / f0
+		} else {
+			g = -a / (1 - f0)
+		}
+		if g == 0 {
+			continue
+		}
+
+		switch {
+		case k < numOrigVars && !split[k]:
+			if table.atUpper[k] {
+				// x'_k = u_k - c_k
+				constant += g * table.upperBound(k)
+				g = -g
+			}
+			column(k, g)
+		case k < firstSlack:
+			// Only the difference of the two parts of a free variable is known from x
+			return nil
+		default:
+			// The slack of row r is (b_r - sum a_rj*c_j) / s_r, where the two parts of a free variable
+			// appear with opposite coefficients and add up to the variable itself
+			r := k - firstSlack
+			coeffRow := lp.ConstraintCoeff.Rows[r]
+			scale := g / coeffRow.At(k)
+			constant += scale * lp.Rhs[r]
+			for idx, j := range coeffRow.Indices {
+				if j < numOrigVars {
+					column(j, -scale*coeffRow.Values[idx])
+				}
+			}
+		}
+	}
+
+	for j, coeff := range coeffs {
+		if math.Abs(coeff) <= 1e-9 {
+			coeffs[j] = 0
+		}
+	}
+	return &Cut{Coeffs: coeffs, Rhs: 1 - constant}
+}
+
+// integerRelaxation returns a copy of the problem to solve as an LP, with the bounds of the integer variables
+// rounded inward so that they stay integer in the tableau.
+func integerRelaxation(lp *model.LinearProgram, tolerance float64) *model.LinearProgram {
+	relaxation := lp.Clone()
+	relaxation.Signs = nil
+	relaxation.LowerBounds = make([]float64, lp.NbVariables)
+	relaxation.UpperBounds = make([]float64, lp.NbVariables)
+	for j := range relaxation.LowerBounds {
+		lower, upper := lp.Bounds(j)
+		if lp.IsInteger(j) {
+			lower, upper = math.Ceil(lower-tolerance), math.Floor(upper+tolerance)
+		}
+		relaxation.LowerBounds[j], relaxation.UpperBounds[j] = lower, upper
+	}
+	return relaxation
+}
+
+// gomoryRound solves the LP relaxation of the problem on the tableau and returns the values of the original
+// variables at its optimum together with the Gomory cuts of the optimal tableau.
+func gomoryRound(lp *model.LinearProgram, opts SolveOptions, tolerance float64) ([]float64, []Cut, error) {
+	relaxation := integerRelaxation(lp, tolerance)
+	table, err := optimalTableau(relaxation, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	solution := table.ExtractSolution(relaxation)
+	return relaxation.OriginalValues(solution[:len(solution)-1]), table.GomoryCuts(relaxation, tolerance), nil
+}
+
+// addGomoryCuts adds rounds of Gomory cuts to the problem until the optimum of its LP relaxation is integer,
+// no cut is found or maxRounds rounds were added.
+func addGomoryCuts(lp *model.LinearProgram, opts SolveOptions, maxRounds int, tolerance float64) (*CuttingPlaneStats, error) {
+	stats := &CuttingPlaneStats{}
+	for {
+		values, cuts, err := gomoryRound(lp, opts, tolerance)
+		if err != nil {
+			return stats, err
+		}
+
+		stats.Integer = true
+		for j, value := range values {
+			if lp.IsInteger(j) && math.Abs(value-math.Round(value)) > tolerance {
+				stats.Integer = false
+			}
+		}
+		if stats.Integer || len(cuts) == 0 || stats.Rounds >= maxRounds {
+			return stats, nil
+		}
+
+		for _, cut := range cuts {
+			lp.AddConstraint(cut.Coeffs, model.BE, cut.Rhs, cut.String(lp))
+		}
+		stats.Rounds++
+		stats.Cuts += len(cuts)
+	}
+}
+
+// SolveCuttingPlanes solves a problem with integer variables by the cutting-plane method: it solves the LP
+// relaxation on the tableau, adds the Gomory cuts of the optimal tableau to the problem as >= constraints and
+// re-optimizes, until the optimum is integer, no cut is found or MaxRounds rounds were added. The problem keeps
+// its cuts, gets the bounds of its integer variables rounded inward and finally receives the solution of the last
+// relaxation in ObjVar and the other solution fields, without being converted. Stats.Integer tells whether the solution is integer;
+// otherwise it is the bound of the last relaxation and SolveMIP can take over.
+func SolveCuttingPlanes(lp *model.LinearProgram, opts CuttingPlaneOptions) (*CuttingPlaneStats, error) {
+	maxRounds := opts.MaxRounds
+	if maxRounds <= 0 {
+		maxRounds = DefaultCutRounds
+	}
+	tolerance := MIPOptions{IntegralityTolerance: opts.IntegralityTolerance}.integralityTolerance()
+
+	stats, err := addGomoryCuts(lp, opts.LP, maxRounds, tolerance)
+	if err != nil {
+		var infeasible *InfeasibleError
+		if errors.As(err, &infeasible) && stats.Rounds > 0 {
+			// The cuts exclude no integer solution, so none exists
+			return stats, &InfeasibleError{}
+		}
+		return stats, err
+	}
+
+	relaxation := integerRelaxation(lp, tolerance)
+	lp.Signs, lp.LowerBounds, lp.UpperBounds = nil, relaxation.LowerBounds, relaxation.UpperBounds
+	lpOpts := opts.LP
+	if lpOpts.Algorithm != DualSimplex {
+		lpOpts.Algorithm = PrimalSimplex
+	}
+	solved := lp.Clone()
+	err = solve(solved, lpOpts, newLimits(context.Background(), lpOpts))
+	copySolution(lp, solved)
+	return stats, err
+}
@@ -0,0 +1,218 @@
+package solver
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestSolveCuttingPlanes(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func() *model.LinearProgram
+		solution []float64
+	}{
+		{
+			// maximize x2 subject to 3x1 + 2x2 <= 6, -3x1 + 2x2 <= 0, whose relaxation optimum is (1, 1.5)
+			name: "Textbook",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints: 2,
+					NbVariables:   2,
+					VariableNames: []string{"x1", "x2"},
+					Objective:     model.MAXIMIZE,
+					ObjCoeff:      []float64{0, 1},
+					Comparisons:   []model.Comparison{model.LE, model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+						{3, 2},
+						{-3, 2},
+					}),
+					Rhs:   []float64{6, 0},
+					Types: []model.VariableType{model.Integer, model.Integer},
+				}
+			},
+			solution: []float64{1, 1, 1},
+		},
+		{
+			// The GeneralInteger problem of TestSolveMIP, with the bounds 1 <= x <= 4 shifting the tableau
+			name: "ShiftedBounds",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints: 2,
+					NbVariables:   2,
+					VariableNames: []string{"x", "y"},
+					Objective:     model.MAXIMIZE,
+					ObjCoeff:      []float64{5, 8},
+					Comparisons:   []model.Comparison{model.LE, model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+						{1, 1},
+						{5, 9},
+					}),
+					Rhs:         []float64{6, 45},
+					LowerBounds: []float64{1, 0},
+					UpperBounds: []float64{4, math.Inf(1)},
+					Types:       []model.VariableType{model.Integer, model.Integer},
+				}
+			},
+			solution: []float64{3, 3, 39},
+		},
+		{
+			// The MixedMinimize problem of TestSolveMIP, with a continuous variable and >= constraints
+			name: "MixedMinimize",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints: 2,
+					NbVariables:   2,
+					VariableNames: []string{"x", "y"},
+					Objective:     model.MINIMIZE,
+					ObjCoeff:      []float64{4, 3},
+					Comparisons:   []model.Comparison{model.BE, model.BE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+						{2, 1},
+						{1, 3},
+					}),
+					Rhs:   []float64{3.5, 3},
+					Types: []model.VariableType{model.Integer, model.Continuous},
+				}
+			},
+			solution: []float64{1, 1.5, 8.5},
+		},
+	}
+
+	algorithms := map[string]SolveOptions{
+		"PrimalSimplex": {Algorithm: PrimalSimplex},
+		"BigM":          {Algorithm: PrimalSimplex, Initialization: BigM},
+		"Devex":         {Algorithm: PrimalSimplex, Pricing: DevexPricing},
+	}
+
+	for _, tt := range tests {
+		for name, opts := range algorithms {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				lp := tt.build()
+				original := tt.build()
+				stats, err := SolveCuttingPlanes(lp, CuttingPlaneOptions{LP: opts})
+				if err != nil {
+					t.Fatalf("SolveCuttingPlanes() error = %v", err)
+				}
+				if !stats.Integer || stats.Cuts == 0 || stats.Rounds == 0 {
+					t.Errorf("Expected an integer solution after some cuts, got %+v", stats)
+				}
+				if !equalFloat64Slices(lp.ObjVar, tt.solution, 1e-6) {
+					t.Errorf("Expected %v, got %v", tt.solution, lp.ObjVar)
+				}
+				if lp.NbOriginalConstraints() != original.NbConstraints+stats.Cuts {
+					t.Errorf("Expected %d cuts to be added to the problem, got %d constraints", stats.Cuts, lp.NbOriginalConstraints())
+				}
+			})
+		}
+	}
+}
+
+func TestGomoryCuts_Valid(t *testing.T) {
+	// Every cut of the first rounds must keep all the integer points of 0 <= x, y <= 6 that satisfy the problem
+	lp := &model.LinearProgram{
+		NbConstraints: 2,
+		NbVariables:   2,
+		VariableNames: []string{"x", "y"},
+		Objective:     model.MAXIMIZE,
+		ObjCoeff:      []float64{5, 8},
+		Comparisons:   []model.Comparison{model.LE, model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 1},
+			{5, 9},
+		}),
+		Rhs:   []float64{6, 45},
+		Types: []model.VariableType{model.Integer, model.Integer},
+	}
+
+	for round := 0; round < 5; round++ {
+		values, cuts, err := gomoryRound(lp, SolveOptions{}, DefaultIntegralityTolerance)
+		if err != nil {
+			t.Fatalf("gomoryRound() error = %v", err)
+		}
+		if len(cuts) == 0 {
+			break
+		}
+		for _, cut := range cuts {
+			if dot(cut.Coeffs, values) >= cut.Rhs-1e-9 {
+				t.Errorf("Expected the cut %s to cut off %v", cut.String(lp), values)
+			}
+			for x := 0.0; x <= 6; x++ {
+				for y := 0.0; x+y <= 6 && 5*x+9*y <= 45; y++ {
+					if dot(cut.Coeffs, []float64{x, y}) < cut.Rhs-1e-9 {
+						t.Errorf("The cut %s excludes the integer point (%v, %v)", cut.String(lp), x, y)
+					}
+				}
+			}
+			lp.AddConstraint(cut.Coeffs, model.BE, cut.Rhs, cut.String(lp))
+		}
+	}
+}
+
+func TestGomoryCuts_String(t *testing.T) {
+	lp := &model.LinearProgram{NbVariables: 3, VariableNames: []string{"x", "y", "z"}}
+	cut := Cut{Coeffs: []float64{-1, 0, 2.5}, Rhs: 3}
+	if got := cut.String(lp); got != "-x + 2.5*z >= 3" {
+		t.Errorf("Expected \"-x + 2.5*z >= 3\", got %q", got)
+	}
+}
+
+func TestSolveMIP_RootCuts(t *testing.T) {
+	build := func() *model.LinearProgram {
+		return &model.LinearProgram{
+			NbConstraints:   1,
+			NbVariables:     4,
+			VariableNames:   []string{"a", "b", "c", "d"},
+			Objective:       model.MAXIMIZE,
+			ObjCoeff:        []float64{10, 13, 7, 8},
+			Comparisons:     []model.Comparison{model.LE},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{3, 4, 2, 3}}),
+			Rhs:             []float64{7},
+			Types:           []model.VariableType{model.Binary, model.Binary, model.Binary, model.Binary},
+		}
+	}
+
+	plain, err := SolveMIP(build(), MIPOptions{})
+	if err != nil {
+		t.Fatalf("SolveMIP() error = %v", err)
+	}
+	lp := build()
+	result, err := SolveMIP(lp, MIPOptions{RootCutRounds: 5})
+	if err != nil {
+		t.Fatalf("SolveMIP() with root cuts error = %v", err)
+	}
+	if !equalFloat64Slices(result.ObjVar, []float64{1, 1, 0, 0, 23}, 1e-6) {
+		t.Errorf("Expected [1 1 0 0 23], got %v", result.ObjVar)
+	}
+	if result.MIP.Nodes > plain.MIP.Nodes {
+		t.Errorf("Expected the root cuts not to grow the tree, got %d nodes instead of %d", result.MIP.Nodes, plain.MIP.Nodes)
+	}
+
+	if !reflect.DeepEqual(lp, build()) {
+		t.Errorf("Expected SolveMIP() to leave the problem unchanged")
+	}
+
+	// 2x - 2y = 1 has no integer solution, which the cuts prove without branching
+	infeasible := &model.LinearProgram{
+		NbConstraints:   1,
+		NbVariables:     2,
+		VariableNames:   []string{"x", "y"},
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        []float64{1, 1},
+		Comparisons:     []model.Comparison{model.EQ},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{2, -2}}),
+		Rhs:             []float64{1},
+		UpperBounds:     []float64{10, 10},
+		Types:           []model.VariableType{model.Integer, model.Integer},
+	}
+	var infeasibleErr *InfeasibleError
+	if _, err := SolveCuttingPlanes(infeasible.Clone(), CuttingPlaneOptions{}); !errors.As(err, &infeasibleErr) {
+		t.Errorf("Expected SolveCuttingPlanes() to return an InfeasibleError, got %v", err)
+	}
+	if _, err := SolveMIP(infeasible, MIPOptions{RootCutRounds: 5}); !errors.As(err, &infeasibleErr) {
+		t.Errorf("Expected SolveMIP() to return an InfeasibleError, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package solver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// IIS is an irreducible infeasible subsystem: a set of constraints and variable bounds that cannot be
+// satisfied together, but can as soon as any one of them is removed.
+type IIS struct {
+	Constraints []IISConstraint `json:"constraints"`
+	Bounds      []IISBound      `json:"bounds"`
+}
+
+// IISConstraint is a constraint of an IIS, with its source text when the problem was parsed.
+type IISConstraint struct {
+	Index int    `json:"index"`
+	Text  string `json:"text,omitempty"`
+}
+
+// IISBound holds the bounds of a variable of an IIS, including the one implied by its sign.
+type IISBound struct {
+	Index    int         `json:"index"`
+	Variable string      `json:"variable"`
+	Bounds   model.Range `json:"bounds"`
+}
+
+// GetJSON returns the IIS in JSON format.
+func (iis *IIS) GetJSON() (string, error) {
+	jsonBytes, err := json.Marshal(iis)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
+
+// FindIIS returns an irreducible infeasible subsystem of an infeasible problem, which is left unchanged.
+// The constraints and bounds outside the support of the Farkas certificate are dropped first, then the
+// deletion filter removes the remaining ones one at a time and puts back those whose removal makes the
+// subsystem feasible. Every solve of a still infeasible subsystem shrinks it to its new certificate.
+func FindIIS(lp *model.LinearProgram) (*IIS, error) {
+	if err := lp.CheckBounds(); err != nil {
+		// A variable whose lower bound is above its upper bound is infeasible on its own
+		for j := 0; j < lp.NbVariables; j++ {
+			if lower, upper := lp.Bounds(j); lower > upper {
+				return newIIS(lp, make([]bool, lp.NbConstraints), boundMembers(j, lp.NbVariables)), nil
+			}
+		}
+		return nil, err
+	}
+
+	constraints := make([]bool, lp.NbConstraints)
+	for i := range constraints {
+		constraints[i] = true
+	}
+	bounds := make([]bool, lp.NbVariables)
+	for j := range bounds {
+		lower, upper := lp.Bounds(j)
+		bounds[j] = !math.IsInf(lower, -1) || !math.IsInf(upper, 1)
+	}
+
+	infeasible, err := testSubsystem(lp, constraints, bounds)
+	if err != nil {
+		return nil, err
+	}
+	if !infeasible {
+		return nil, fmt.Errorf("the problem is feasible")
+	}
+
+	for _, members := range [][]bool{constraints, bounds} {
+		for k := range members {
+			if !members[k] {
+				continue
+			}
+			members[k] = false
+			infeasible, err := testSubsystem(lp, constraints, bounds)
+			if err != nil {
+				return nil, err
+			}
+			members[k] = !infeasible
+		}
+	}
+
+	return newIIS(lp, constraints, bounds), nil
+}
+
+// boundMembers returns the bound membership of an IIS made of the bounds of variable j alone.
+func boundMembers(j, nbVariables int) []bool {
+	bounds := make([]bool, nbVariables)
+	bounds[j] = true
+	return bounds
+}
+
+// testSubsystem reports whether the constraints and bounds that are kept cannot be satisfied together.
+// When they cannot and the Farkas certificate checks out, the members outside its support are removed.
+func testSubsystem(lp *model.LinearProgram, constraints, bounds []bool) (bool, error) {
+	sub, rows := subsystem(lp, constraints, bounds)
+	if sub.NbConstraints == 0 {
+		return false, nil // Consistent bounds alone are always feasible
+	}
+
+	_, err := Solve(sub)
+	var infeasible *InfeasibleError
+	if !errors.As(err, &infeasible) {
+		return false, err
+	}
+	if CheckCertificate(sub, err) != nil {
+		return true, nil
+	}
+
+	epsilon := 1e-9
+	combined := make([]float64, lp.NbVariables)
+	for k, i := range rows {
+		if math.Abs(infeasible.Farkas[k]) <= epsilon {
+			constraints[i] = false
+			continue
+		}
+		row := lp.ConstraintCoeff.Rows[i]
+		for l, j := range row.Indices {
+			combined[j] += infeasible.Farkas[k] * row.Values[l]
+		}
+	}
+	for j := range bounds {
+		if math.Abs(combined[j]) <= epsilon {
+			bounds[j] = false
+		}
+	}
+	return true, nil
+}
+
+// subsystem returns a feasibility problem with the constraints that are kept, and the original constraint
+// of each of its rows. The variables whose bounds are not kept are free.
+func subsystem(lp *model.LinearProgram, constraints, bounds []bool) (*model.LinearProgram, []int) {
+	var rows []int
+	for i, kept := range constraints {
+		if kept {
+			rows = append(rows, i)
+		}
+	}
+
+	sub := &model.LinearProgram{
+		NbConstraints:   len(rows),
+		NbVariables:     lp.NbVariables,
+		VariableNames:   lp.VariableNames,
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        make([]float64, lp.NbVariables),
+		ConstraintCoeff: model.NewSparseMatrix(len(rows), lp.NbVariables),
+		LowerBounds:     make([]float64, lp.NbVariables),
+		UpperBounds:     make([]float64, lp.NbVariables),
+	}
+	for k, i := range rows {
+		sub.Comparisons = append(sub.Comparisons, lp.Comparisons[i])
+		sub.Rhs = append(sub.Rhs, lp.Rhs[i])
+		sub.ConstraintCoeff.Rows[k] = lp.ConstraintCoeff.Rows[i].Clone()
+	}
+	for j, kept := range bounds {
+		sub.LowerBounds[j], sub.UpperBounds[j] = math.Inf(-1), math.Inf(1)
+		if kept {
+			sub.LowerBounds[j], sub.UpperBounds[j] = lp.Bounds(j)
+		}
+	}
+	return sub, rows
+}
+
+// newIIS reports the constraints and bounds that are kept.
+func newIIS(lp *model.LinearProgram, constraints, bounds []bool) *IIS {
+	iis := &IIS{Constraints: []IISConstraint{}, Bounds: []IISBound{}}
+	for i, kept := range constraints {
+		if !kept {
+			continue
+		}
+		constraint := IISConstraint{Index: i}
+		if i < len(lp.ConstraintTexts) {
+			constraint.Text = lp.ConstraintTexts[i]
+		}
+		iis.Constraints = append(iis.Constraints, constraint)
+	}
+	for j, kept := range bounds {
+		if !kept {
+			continue
+		}
+		lower, upper := lp.Bounds(j)
+		iis.Bounds = append(iis.Bounds, IISBound{
+			Index:    j,
+			Variable: lp.VariableName(j),
+			Bounds:   model.Range{Lower: lower, Upper: upper},
+		})
+	}
+	return iis
+}
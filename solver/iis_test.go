@@ -0,0 +1,152 @@
+package solver
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestFindIIS(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name        string
+		lp          *model.LinearProgram
+		constraints []int
+		bounds      []int
+	}{
+		{
+			name: "Constraints",
+			lp: &model.LinearProgram{
+				NbConstraints: 5,
+				NbVariables:   3,
+				VariableNames: []string{"x", "y", "z"},
+				Objective:     model.MAXIMIZE,
+				ObjCoeff:      []float64{1, 1, 1},
+				Comparisons:   []model.Comparison{model.LE, model.LE, model.BE, model.BE, model.BE},
+				ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+					{0, 0, 1},
+					{1, 1, 0},
+					{1, 0, 0},
+					{1, 0, 1},
+					{0, 1, 0},
+				}),
+				Rhs:             []float64{100, 10, 2, 1, 9},
+				ConstraintTexts: []string{"z <= 100", "x + y <= 10", "x >= 2", "x + z >= 1", "y >= 9"},
+			},
+			constraints: []int{1, 2, 4},
+			bounds:      []int{},
+		},
+		{
+			name: "ConstraintsAndBounds",
+			lp: &model.LinearProgram{
+				NbConstraints: 3,
+				NbVariables:   3,
+				VariableNames: []string{"x", "y", "z"},
+				Objective:     model.MINIMIZE,
+				ObjCoeff:      []float64{1, 1, 1},
+				Comparisons:   []model.Comparison{model.LE, model.EQ, model.BE},
+				ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+					{0, 0, 1},
+					{1, 1, 0},
+					{1, 0, 1},
+				}),
+				Rhs:         []float64{5, 10, 1},
+				Signs:       []model.Sign{model.NonNegative, model.Free, model.NonNegative},
+				LowerBounds: []float64{0, -inf, 1},
+				UpperBounds: []float64{3, 4, 2},
+			},
+			constraints: []int{1},
+			bounds:      []int{0, 1},
+		},
+		{
+			name: "InconsistentBounds",
+			lp: &model.LinearProgram{
+				NbConstraints:   1,
+				NbVariables:     2,
+				VariableNames:   []string{"x", "y"},
+				Objective:       model.MAXIMIZE,
+				ObjCoeff:        []float64{1, 1},
+				Comparisons:     []model.Comparison{model.LE},
+				ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}}),
+				Rhs:             []float64{10},
+				LowerBounds:     []float64{0, 5},
+				UpperBounds:     []float64{inf, 4},
+			},
+			constraints: []int{},
+			bounds:      []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		original := tt.lp.Clone()
+		iis, err := FindIIS(tt.lp)
+		if err != nil {
+			t.Fatalf("%s: FindIIS() error = %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(tt.lp, original) {
+			t.Errorf("%s: Expected FindIIS() to leave the problem unchanged", tt.name)
+		}
+
+		constraints := []int{}
+		for _, constraint := range iis.Constraints {
+			constraints = append(constraints, constraint.Index)
+			if constraint.Index < len(tt.lp.ConstraintTexts) && constraint.Text != tt.lp.ConstraintTexts[constraint.Index] {
+				t.Errorf("%s: Expected text %q for constraint %d, got %q", tt.name, tt.lp.ConstraintTexts[constraint.Index], constraint.Index, constraint.Text)
+			}
+		}
+		bounds := []int{}
+		for _, bound := range iis.Bounds {
+			bounds = append(bounds, bound.Index)
+		}
+		if !reflect.DeepEqual(constraints, tt.constraints) {
+			t.Errorf("%s: Expected constraints %v, got %v", tt.name, tt.constraints, constraints)
+		}
+		if !reflect.DeepEqual(bounds, tt.bounds) {
+			t.Errorf("%s: Expected bounds of variables %v, got %v", tt.name, tt.bounds, bounds)
+		}
+	}
+}
+
+func TestFindIIS_Feasible(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints:   1,
+		NbVariables:     2,
+		VariableNames:   []string{"x", "y"},
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        []float64{1, 1},
+		Comparisons:     []model.Comparison{model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}}),
+		Rhs:             []float64{10},
+	}
+	if _, err := FindIIS(lp); err == nil || err.Error() != "the problem is feasible" {
+		t.Errorf("Expected error 'the problem is feasible', got %v", err)
+	}
+}
+
+func TestIIS_GetJSON(t *testing.T) {
+	iis := &IIS{
+		Constraints: []IISConstraint{{Index: 1, Text: "x + y >= 10"}},
+		Bounds:      []IISBound{{Index: 0, Variable: "x", Bounds: model.Range{Lower: 0, Upper: 3}}},
+	}
+	jsonString, err := iis.GetJSON()
+	if err != nil {
+		t.Fatalf("GetJSON() error = %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonString), &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	expected := map[string]interface{}{
+		"constraints": []interface{}{map[string]interface{}{"index": 1.0, "text": "x + y >= 10"}},
+		"bounds": []interface{}{map[string]interface{}{
+			"index": 0.0, "variable": "x", "bounds": map[string]interface{}{"lower": 0.0, "upper": 3.0},
+		}},
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("Expected %v, got %s", expected, jsonString)
+	}
+}
@@ -0,0 +1,473 @@
+package solver
+
+import (
+	"errors"
+	"math"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// MaxInteriorPointIterations is the iteration limit of the interior-point method.
+const MaxInteriorPointIterations = 200
+
+// InteriorPointIteration reports the progress of one interior-point iteration.
+type InteriorPointIteration struct {
+	Iteration      int
+	PrimalResidual float64 // ||A*x - b|| / (1 + ||b||)
+	DualResidual   float64 // ||A^T*y + s - c|| / (1 + ||c||)
+	DualityGap     float64 // |c*x - b*y| / (1 + |c*x|)
+	Mu             float64 // x*s / n
+}
+
+// InteriorPointSolver solves a linear program in slack form with Mehrotra's predictor-corrector
+// primal-dual interior-point method, working on the standard form
+// minimize c*x subject to A*x = b, x >= 0, with dual variables y and s.
+type InteriorPointSolver struct {
+	numRows int
+	numCols int
+	a       *model.SparseMatrix
+	columns []model.SparseVector // columns of a, for products with A^T
+	b       []float64
+	c       []float64
+	x       []float64
+	y       []float64
+	s       []float64
+	History []InteriorPointIteration
+	limits  *limits // nil never stops the solve
+	// Row of the slack form of each constraint row of the standard form, and the row of the slack form holding
+	// the other half of a merged equality, -1 if none
+	rows    []int
+	mirrors []int
+	// The equality rows contradict each other, which makes the problem infeasible
+	inconsistent bool
+}
+
+// Initialize builds the standard form from a linear program in slack form.
+// The two rows an equality was split into are merged back into one row without slack variables, as their slacks
+// can only be zero and would leave the method without an interior point. Equality rows that depend on the others
+// are removed, so that A has full row rank. Finite upper bounds are added as rows x + w = u with a new column for w.
+func (ipm *InteriorPointSolver) Initialize(problem *model.LinearProgram) {
+	if problem.State != model.Slack {
+		problem.ToSlackForm()
+	}
+
+	m := problem.NbConstraints
+	n := problem.NbVariables
+	firstSlack := n - m
+
+	// Pair the rows of the slack form that come from the same equality
+	mirrors := make([]int, m)
+	merged := make([]bool, m)
+	for i := range mirrors {
+		mirrors[i] = -1
+	}
+	if problem.RowOrigins != nil {
+		for i := 0; i < m; i++ {
+			for k := i + 1; k < m && mirrors[i] == -1 && !merged[i]; k++ {
+				if !merged[k] && problem.RowOrigins[k] == problem.RowOrigins[i] && problem.RowSigns[k] == -problem.RowSigns[i] {
+					mirrors[i], merged[k] = k, true
+				}
+			}
+		}
+	}
+	ipm.rows, ipm.mirrors = nil, nil
+	for i := 0; i < m; i++ {
+		if !merged[i] {
+			ipm.rows = append(ipm.rows, i)
+			ipm.mirrors = append(ipm.mirrors, mirrors[i])
+		}
+	}
+	ipm.removeDependentRows(problem)
+
+	// The slacks of the merged rows are dropped, the other columns keep their index
+	columnOf := make([]int, n)
+	numCols := 0
+	for j := 0; j < n; j++ {
+		if slackRow := j - firstSlack; slackRow >= 0 && (merged[slackRow] || mirrors[slackRow] != -1) {
+			columnOf[j] = -1
+			continue
+		}
+		columnOf[j] = numCols
+		numCols++
+	}
+
+	var bounded []int
+	for j := 0; j < n; j++ {
+		if _, upper := problem.Bounds(j); !math.IsInf(upper, 1) && columnOf[j] != -1 {
+			bounded = append(bounded, j)
+		}
+	}
+
+	numConstraintRows := len(ipm.rows)
+	ipm.numRows = numConstraintRows + len(bounded)
+	ipm.numCols = numCols + len(bounded)
+	ipm.a = model.NewSparseMatrix(ipm.numRows, ipm.numCols)
+	ipm.b = make([]float64, ipm.numRows)
+	ipm.c = make([]float64, ipm.numCols)
+
+	for r, i := range ipm.rows {
+		row := problem.ConstraintCoeff.Rows[i]
+		for p, j := range row.Indices {
+			if columnOf[j] != -1 {
+				ipm.a.Set(r, columnOf[j], row.Values[p])
+			}
+		}
+		ipm.b[r] = problem.Rhs[i]
+	}
+	for k, j := range bounded {
+		row := numConstraintRows + k
+		ipm.a.Set(row, columnOf[j], 1)
+		ipm.a.Set(row, numCols+k, 1)
+		_, ipm.b[row] = problem.Bounds(j)
+	}
+	ipm.columns = ipm.a.Transpose().Rows
+
+	// The slack form maximizes, the standard form minimizes
+	for j := 0; j < n; j++ {
+		if columnOf[j] != -1 {
+			ipm.c[columnOf[j]] = -problem.ObjCoeff[j]
+		}
+	}
+
+	ipm.History = nil
+	ipm.startingPoint()
+}
+
+// removeDependentRows removes the merged equality rows that are linear combinations of the previous ones. Only
+// they can be dependent, since every other row has a slack variable of its own. A dependent row whose RHS does not
+// follow the same combination makes the problem infeasible.
+func (ipm *InteriorPointSolver) removeDependentRows(problem *model.LinearProgram) {
+	numOrigVars := problem.NbVariables - problem.NbConstraints
+	epsilon := 1e-9
+
+	ipm.inconsistent = false
+	var pivotRows [][]float64 // Reduced rows, followed by the RHS
+	var pivotCols []int
+	var rows, mirrors []int
+	for r, i := range ipm.rows {
+		if ipm.mirrors[r] == -1 {
+			rows, mirrors = append(rows, i), append(mirrors, -1)
+			continue
+		}
+
+		reduced := make([]float64, numOrigVars+1)
+		coeffs := problem.ConstraintCoeff.Rows[i]
+		for p, j := range coeffs.Indices {
+			if j < numOrigVars {
+				reduced[j] = coeffs.Values[p]
+			}
+		}
+		reduced[numOrigVars] = problem.Rhs[i]
+		scale := 1 + norm(reduced)
+
+		for k, pivotRow := range pivotRows {
+			if factor := reduced[pivotCols[k]]; factor != 0 {
+				for j := range reduced {
+					reduced[j] -= factor * pivotRow[j]
+				}
+			}
+		}
+
+		pivotCol := 0
+		for j := 1; j < numOrigVars; j++ {
+			if math.Abs(reduced[j]) > math.Abs(reduced[pivotCol]) {
+				pivotCol = j
+			}
+		}
+		if numOrigVars == 0 || math.Abs(reduced[pivotCol]) <= epsilon*scale {
+			if math.Abs(reduced[numOrigVars]) > epsilon*scale {
+				ipm.inconsistent = true
+			}
+			continue
+		}
+
+		pivot := reduced[pivotCol]
+		for j := range reduced {
+			reduced[j] /= pivot
+		}
+		pivotRows = append(pivotRows, reduced)
+		pivotCols = append(pivotCols, pivotCol)
+		rows, mirrors = append(rows, i), append(mirrors, ipm.mirrors[r])
+	}
+	ipm.rows, ipm.mirrors = rows, mirrors
+}
+
+// startingPoint computes Mehrotra's starting point: the least squares solutions of A*x = b
+// and A^T*y + s = c, shifted to be strictly positive.
+func (ipm *InteriorPointSolver) startingPoint() {
+	ones := make([]float64, ipm.numCols)
+	for j := range ones {
+		ones[j] = 1
+	}
+	l := ipm.choleskyFactor(ones)
+
+	ipm.x = ipm.multiplyTranspose(choleskySolve(l, ipm.b))
+	ipm.y = choleskySolve(l, ipm.multiply(ipm.c))
+	ipm.s = make([]float64, ipm.numCols)
+	aty := ipm.multiplyTranspose(ipm.y)
+	for j := range ipm.s {
+		ipm.s[j] = ipm.c[j] - aty[j]
+	}
+
+	shift := func(v []float64) {
+		shiftBy := 0.0
+		for _, value := range v {
+			shiftBy = math.Max(shiftBy, -1.5*value)
+		}
+		for j := range v {
+			v[j] += shiftBy
+		}
+	}
+	shift(ipm.x)
+	shift(ipm.s)
+
+	xs, sumX, sumS := dot(ipm.x, ipm.s), 0.0, 0.0
+	for j := range ipm.x {
+		sumX += ipm.x[j]
+		sumS += ipm.s[j]
+	}
+	for j := range ipm.x {
+		if xs > 0 {
+			ipm.x[j] += 0.5 * xs / sumS
+			ipm.s[j] += 0.5 * xs / sumX
+		} else {
+			ipm.x[j] += 1
+			ipm.s[j] += 1
+		}
+	}
+}
+
+// errNotConverged is returned by InteriorPointSolver.Solve after MaxInteriorPointIterations iterations.
+var errNotConverged = errors.New("interior point method did not converge")
+
+// Solve runs predictor-corrector iterations until the residuals and the duality gap are small.
+// Inconsistent equality rows, and iterates that diverge along an improving direction, only suggest that the
+// problem is infeasible or unbounded: the errors returned for them carry no certificate, and solveInteriorPoint
+// has the simplex method confirm them.
+// Every iteration is appended to History and passed to the log function, if any.
+func (ipm *InteriorPointSolver) Solve(log func(InteriorPointIteration)) error {
+	if ipm.inconsistent {
+		return &InfeasibleError{}
+	}
+
+	tolerance := 1e-8
+	normB, normC := norm(ipm.b), norm(ipm.c)
+	divergence := 1e8 * (1 + normB + normC)
+
+	for iteration := 1; iteration <= MaxInteriorPointIterations; iteration++ {
+		rb := ipm.multiply(ipm.x)
+		for i := range rb {
+			rb[i] -= ipm.b[i]
+		}
+		rc := ipm.multiplyTranspose(ipm.y)
+		for j := range rc {
+			rc[j] += ipm.s[j] - ipm.c[j]
+		}
+		mu := dot(ipm.x, ipm.s) / float64(ipm.numCols)
+		primalObjective := dot(ipm.c, ipm.x)
+		dualObjective := dot(ipm.b, ipm.y)
+
+		progress := InteriorPointIteration{
+			Iteration:      iteration,
+			PrimalResidual: norm(rb) / (1 + normB),
+			DualResidual:   norm(rc) / (1 + normC),
+			DualityGap:     math.Abs(primalObjective-dualObjective) / (1 + math.Abs(primalObjective)),
+			Mu:             mu,
+		}
+		ipm.History = append(ipm.History, progress)
+		if log != nil {
+			log(progress)
+		}
+
+		if progress.PrimalResidual < tolerance && progress.DualResidual < tolerance && progress.DualityGap < tolerance {
+			return nil
+		}
+		if dualObjective > divergence {
+			return &InfeasibleError{} // The dual is unbounded, without a certificate
+		}
+		if primalObjective < -divergence {
+			return &UnboundedError{}
+		}
+		if err := ipm.limits.step(); err != nil {
+			return err
+		}
+
+		d := make([]float64, ipm.numCols)
+		for j := range d {
+			d[j] = ipm.x[j] / ipm.s[j]
+		}
+		l := ipm.choleskyFactor(d)
+
+		// Predictor: affine scaling direction
+		rxs := make([]float64, ipm.numCols)
+		for j := range rxs {
+			rxs[j] = -ipm.x[j] * ipm.s[j]
+		}
+		dxAff, _, dsAff := ipm.newtonDirection(l, d, rb, rc, rxs)
+		alphaPrimal := math.Min(1, maxStep(ipm.x, dxAff))
+		alphaDual := math.Min(1, maxStep(ipm.s, dsAff))
+
+		muAff := 0.0
+		for j := range ipm.x {
+			muAff += (ipm.x[j] + alphaPrimal*dxAff[j]) * (ipm.s[j] + alphaDual*dsAff[j])
+		}
+		muAff /= float64(ipm.numCols)
+		sigma := math.Pow(muAff/mu, 3)
+
+		// Corrector: centering and second order terms
+		for j := range rxs {
+			rxs[j] = -ipm.x[j]*ipm.s[j] - dxAff[j]*dsAff[j] + sigma*mu
+		}
+		dx, dy, ds := ipm.newtonDirection(l, d, rb, rc, rxs)
+		alphaPrimal = math.Min(1, 0.99*maxStep(ipm.x, dx))
+		alphaDual = math.Min(1, 0.99*maxStep(ipm.s, ds))
+
+		for j := range ipm.x {
+			ipm.x[j] += alphaPrimal * dx[j]
+			ipm.s[j] += alphaDual * ds[j]
+		}
+		for i := range ipm.y {
+			ipm.y[i] += alphaDual * dy[i]
+		}
+	}
+
+	return errNotConverged
+}
+
+// newtonDirection solves the Newton system
+// A*dx = -rb, A^T*dy + ds = -rc, S*dx + X*ds = rxs
+// through the normal equations A*D*A^T*dy = -rb - A*(S^-1*rxs + D*rc), with D = X*S^-1 factorized in l.
+func (ipm *InteriorPointSolver) newtonDirection(l [][]float64, d, rb, rc, rxs []float64) ([]float64, []float64, []float64) {
+	v := make([]float64, ipm.numCols)
+	for j := range v {
+		v[j] = rxs[j]/ipm.s[j] + d[j]*rc[j]
+	}
+	rhs := ipm.multiply(v)
+	for i := range rhs {
+		rhs[i] = -rb[i] - rhs[i]
+	}
+	dy := choleskySolve(l, rhs)
+
+	aty := ipm.multiplyTranspose(dy)
+	dx := make([]float64, ipm.numCols)
+	ds := make([]float64, ipm.numCols)
+	for j := range dx {
+		dx[j] = rxs[j]/ipm.s[j] + d[j]*(rc[j]+aty[j])
+		ds[j] = -rc[j] - aty[j]
+	}
+	return dx, dy, ds
+}
+
+// choleskyFactor returns the lower triangular Cholesky factor of A*D*A^T. A has full row rank, but near the optimum
+// the scaling D makes the matrix ill-conditioned: a pivot that cancels down to rounding errors of its diagonal entry
+// is replaced by a huge value, which drops the corresponding direction instead of amplifying the errors.
+func (ipm *InteriorPointSolver) choleskyFactor(d []float64) [][]float64 {
+	m := ipm.numRows
+	l := make([][]float64, m)
+	for i := range l {
+		l[i] = make([]float64, m)
+	}
+	// Each column of A only contributes to the entries between its own non-zeros
+	for j, column := range ipm.columns {
+		for p, i := range column.Indices {
+			for q := 0; q <= p; q++ {
+				l[i][column.Indices[q]] += column.Values[p] * d[j] * column.Values[q]
+			}
+		}
+	}
+
+	for k := 0; k < m; k++ {
+		diagonal := l[k][k]
+		for j := 0; j < k; j++ {
+			l[k][k] -= l[k][j] * l[k][j]
+		}
+		if l[k][k] <= 1e-14*diagonal || l[k][k] <= 1e-30 {
+			l[k][k] = 1e64
+		}
+		l[k][k] = math.Sqrt(l[k][k])
+		for i := k + 1; i < m; i++ {
+			for j := 0; j < k; j++ {
+				l[i][k] -= l[i][j] * l[k][j]
+			}
+			l[i][k] /= l[k][k]
+		}
+	}
+	return l
+}
+
+// choleskySolve returns x such that L*L^T*x = rhs.
+func choleskySolve(l [][]float64, rhs []float64) []float64 {
+	m := len(l)
+	x := make([]float64, m)
+	copy(x, rhs)
+	for i := 0; i < m; i++ {
+		for j := 0; j < i; j++ {
+			x[i] -= l[i][j] * x[j]
+		}
+		x[i] /= l[i][i]
+	}
+	for i := m - 1; i >= 0; i-- {
+		for j := i + 1; j < m; j++ {
+			x[i] -= l[j][i] * x[j]
+		}
+		x[i] /= l[i][i]
+	}
+	return x
+}
+
+// multiply returns A*v.
+func (ipm *InteriorPointSolver) multiply(v []float64) []float64 {
+	result := make([]float64, ipm.numRows)
+	for i, row := range ipm.a.Rows {
+		result[i] = row.Dot(v)
+	}
+	return result
+}
+
+// multiplyTranspose returns A^T*v.
+func (ipm *InteriorPointSolver) multiplyTranspose(v []float64) []float64 {
+	result := make([]float64, ipm.numCols)
+	for j, column := range ipm.columns {
+		result[j] = column.Dot(v)
+	}
+	return result
+}
+
+// maxStep returns the largest step alpha such that v + alpha*dv stays non-negative.
+func maxStep(v, dv []float64) float64 {
+	step := math.Inf(1)
+	for j := range v {
+		if dv[j] < 0 {
+			step = math.Min(step, -v[j]/dv[j])
+		}
+	}
+	return step
+}
+
+func norm(v []float64) float64 {
+	return math.Sqrt(dot(v, v))
+}
+
+// ExtractSolution returns the values of the original variables followed by the objective value.
+func (ipm *InteriorPointSolver) ExtractSolution(problem *model.LinearProgram) []float64 {
+	numOrigVars := problem.NbVariables - problem.NbConstraints
+	solution := make([]float64, numOrigVars+1)
+	copy(solution, ipm.x[:numOrigVars])
+	solution[numOrigVars] = -dot(ipm.c, ipm.x)
+	return solution
+}
+
+// ExtractDuals returns the dual value of each constraint row for the maximized objective.
+// The dual of a merged equality goes to the half it has the sign of, the duals of the removed rows and of the
+// rows added for the upper bounds are left out.
+func (ipm *InteriorPointSolver) ExtractDuals(problem *model.LinearProgram) []float64 {
+	duals := make([]float64, problem.NbConstraints)
+	for r, i := range ipm.rows {
+		dual := -ipm.y[r] // The standard form minimizes -c*x
+		if k := ipm.mirrors[r]; k != -1 && dual < 0 {
+			i, dual = k, -dual
+		}
+		duals[i] = dual
+	}
+	return duals
+}
@@ -0,0 +1,179 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestSolveWithOptions_InteriorPoint(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints: 3,
+		NbVariables:   2,
+		VariableNames: []string{"x", "y"},
+		Objective:     model.MINIMIZE,
+		ObjCoeff:      []float64{2, 3},
+		Comparisons:   []model.Comparison{model.BE, model.LE, model.EQ},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 1},
+			{2, 1},
+			{1, -1},
+		}),
+		Rhs:         []float64{10, 20, 2},
+		UpperBounds: []float64{math.Inf(1), 5},
+	}
+
+	var iterations []InteriorPointIteration
+	result, err := SolveWithOptions(context.Background(), lp, SolveOptions{
+		Algorithm:        InteriorPoint,
+		InteriorPointLog: func(it InteriorPointIteration) { iterations = append(iterations, it) },
+	})
+	if err != nil {
+		t.Fatalf("SolveWithOptions() error = %v", err)
+	}
+
+	// Expected solution for this problem is x=6, y=4, objective=24
+	expectedSolution := []float64{6, 4, 24}
+	if !equalFloat64Slices(result.ObjVar, expectedSolution, 1e-6) {
+		t.Errorf("Expected solution to be %v, got %v", expectedSolution, result.ObjVar)
+	}
+
+	if len(iterations) == 0 {
+		t.Fatalf("Expected the iterations to be reported")
+	}
+	last := iterations[len(iterations)-1]
+	if last.PrimalResidual > 1e-8 || last.DualResidual > 1e-8 || last.DualityGap > 1e-8 {
+		t.Errorf("Expected the last iteration to have converged, got %+v", last)
+	}
+}
+
+func TestSolveWithOptions_InteriorPointStatus(t *testing.T) {
+	t.Run("Unbounded", func(t *testing.T) {
+		lp := &model.LinearProgram{
+			NbConstraints:   2,
+			NbVariables:     2,
+			VariableNames:   []string{"x1", "x2"},
+			Objective:       model.MAXIMIZE,
+			ObjCoeff:        []float64{1, 1},
+			Comparisons:     []model.Comparison{model.LE, model.LE},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, -1}, {-1, 1}}),
+			Rhs:             []float64{1, 1},
+		}
+		_, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: InteriorPoint})
+		if err == nil || err.Error() != "unbounded problem" {
+			t.Errorf("Expected error to be 'unbounded problem', got %v", err)
+		}
+	})
+
+	t.Run("Infeasible", func(t *testing.T) {
+		lp := &model.LinearProgram{
+			NbConstraints:   2,
+			NbVariables:     2,
+			VariableNames:   []string{"x1", "x2"},
+			Objective:       model.MAXIMIZE,
+			ObjCoeff:        []float64{1, 1},
+			Comparisons:     []model.Comparison{model.LE, model.BE},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}, {1, 1}}),
+			Rhs:             []float64{1, 2},
+		}
+		_, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: InteriorPoint})
+		if err == nil || err.Error() != "infeasible problem" {
+			t.Errorf("Expected error to be 'infeasible problem', got %v", err)
+		}
+	})
+
+	t.Run("LargeInfeasible", func(t *testing.T) {
+		// Too large for a dense tableau: the revised simplex confirms the status. Every x_j + x_j+1 <= 1,
+		// so the first ten variables cannot add up to 6
+		m, n := 600, 1800
+		lp := &model.LinearProgram{NbConstraints: m, NbVariables: n, Objective: model.MAXIMIZE}
+		lp.ObjCoeff = make([]float64, n)
+		lp.ConstraintCoeff = model.NewSparseMatrix(m, n)
+		for i := 0; i < m-1; i++ {
+			lp.ConstraintCoeff.Set(i, i, 1)
+			lp.ConstraintCoeff.Set(i, i+1, 1)
+			lp.Comparisons = append(lp.Comparisons, model.LE)
+			lp.Rhs = append(lp.Rhs, 1)
+		}
+		for j := 0; j < 10; j++ {
+			lp.ConstraintCoeff.Set(m-1, j, 1)
+		}
+		lp.Comparisons = append(lp.Comparisons, model.BE)
+		lp.Rhs = append(lp.Rhs, 6)
+		for j := range lp.ObjCoeff {
+			lp.ObjCoeff[j] = 1
+		}
+
+		_, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: InteriorPoint})
+		if !errors.Is(err, ErrInfeasible) {
+			t.Fatalf("Expected an infeasible problem, got %v", err)
+		}
+		if err := CheckCertificate(lp, err); err != nil {
+			t.Errorf("CheckCertificate() error = %v", err)
+		}
+	})
+}
+
+func TestSolveWithOptions_InteriorPointCrossCheck(t *testing.T) {
+	// max y s.t. x <= 1, x >= 3: the dual diverges along the unbounded y before the primal infeasibility shows
+	contradicting := &model.LinearProgram{
+		NbConstraints:   2,
+		NbVariables:     2,
+		VariableNames:   []string{"x", "y"},
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        []float64{0, 1},
+		Comparisons:     []model.Comparison{model.LE, model.BE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 0}, {1, 0}}),
+		Rhs:             []float64{1, 3},
+	}
+
+	// x + y = 2 twice, with a second RHS that contradicts the first one
+	dependent := func(rhs float64) *model.LinearProgram {
+		return &model.LinearProgram{
+			NbConstraints:   3,
+			NbVariables:     2,
+			VariableNames:   []string{"x", "y"},
+			Objective:       model.MAXIMIZE,
+			ObjCoeff:        []float64{1, 2},
+			Comparisons:     []model.Comparison{model.EQ, model.EQ, model.LE},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}, {2, 2}, {0, 1}}),
+			Rhs:             []float64{2, rhs, 1.5},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		lp       *model.LinearProgram
+		sentinel error
+	}{
+		{"Contradicting", contradicting, ErrInfeasible},
+		{"Inconsistent", dependent(5), ErrInfeasible},
+		{"Dependent", dependent(4), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SolveWithOptions(context.Background(), tt.lp, SolveOptions{Algorithm: InteriorPoint})
+			if !errors.Is(err, tt.sentinel) {
+				t.Fatalf("Expected an error matching %v, got %v", tt.sentinel, err)
+			}
+			if err != nil {
+				if err := CheckCertificate(tt.lp, err); err != nil {
+					t.Errorf("Expected a valid certificate, got %v", err)
+				}
+				return
+			}
+
+			// x = 0.5, y = 1.5, with the dual of x + y = 2 on the first equality
+			if !equalFloat64Slices(result.ObjVar, []float64{0.5, 1.5, 3.5}, 1e-6) {
+				t.Errorf("Expected the solution [0.5 1.5 3.5], got %v", result.ObjVar)
+			}
+			if !equalFloat64Slices(result.Duals, []float64{1, 0, 1}, 1e-6) {
+				t.Errorf("Expected the duals [1 0 1], got %v", result.Duals)
+			}
+		})
+	}
+}
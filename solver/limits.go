@@ -0,0 +1,79 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// LimitError is returned when a solve stops at a limit of SolveOptions or of its context. Basis is the last basis
+// the simplex reached, which can be passed as WarmStart to continue the solve later; it is nil for the
+// interior-point method, which has no basis. Err is the error of the context, if it stopped the solve, so that
+// errors.Is(err, context.DeadlineExceeded) holds for a deadline.
+type LimitError struct {
+	Status     Status
+	Iterations int
+	Basis      *model.Basis
+	Err        error
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("solve stopped after %d iterations: %v", e.Iterations, e.Status)
+}
+
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is the sentinel error of the status.
+func (e *LimitError) Is(target error) bool {
+	switch e.Status {
+	case IterationLimit:
+		return target == ErrIterationLimit
+	case TimeLimit:
+		return target == ErrTimeLimit
+	case Canceled:
+		return target == ErrCanceled
+	}
+	return false
+}
+
+// limits counts the iterations of a solve and stops it at the iteration limit, the time limit or when its context
+// is done. A nil limits never stops a solve.
+type limits struct {
+	ctx           context.Context
+	deadline      time.Time // Zero without a time limit
+	maxIterations int       // 0 without an iteration limit
+	iterations    int
+}
+
+// newLimits returns the limits of a solve that starts now.
+func newLimits(ctx context.Context, opts SolveOptions) *limits {
+	l := &limits{ctx: ctx, maxIterations: opts.MaxIterations}
+	if opts.TimeLimit > 0 {
+		l.deadline = time.Now().Add(opts.TimeLimit)
+	}
+	return l
+}
+
+// step is called before every iteration. It returns a *LimitError when the solve must stop instead.
+func (l *limits) step() error {
+	if l == nil {
+		return nil
+	}
+	if l.maxIterations > 0 && l.iterations >= l.maxIterations {
+		return &LimitError{Status: IterationLimit, Iterations: l.iterations}
+	}
+	switch err := l.ctx.Err(); {
+	case err == context.DeadlineExceeded:
+		return &LimitError{Status: TimeLimit, Iterations: l.iterations, Err: err}
+	case err != nil:
+		return &LimitError{Status: Canceled, Iterations: l.iterations, Err: err}
+	case !l.deadline.IsZero() && time.Now().After(l.deadline):
+		return &LimitError{Status: TimeLimit, Iterations: l.iterations, Err: context.DeadlineExceeded}
+	}
+	l.iterations++
+	return nil
+}
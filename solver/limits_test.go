@@ -0,0 +1,90 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSolveWithOptions_IterationLimit(t *testing.T) {
+	algorithms := map[string]SolveOptions{
+		"PrimalSimplex":  {Algorithm: PrimalSimplex},
+		"RevisedSimplex": {Algorithm: RevisedSimplex},
+		"InteriorPoint":  {Algorithm: InteriorPoint},
+	}
+
+	for name, opts := range algorithms {
+		t.Run(name, func(t *testing.T) {
+			opts.MaxIterations = 1
+			_, err := SolveWithOptions(context.Background(), parametricProblem(), opts)
+			var limitErr *LimitError
+			if !errors.As(err, &limitErr) || limitErr.Status != IterationLimit || limitErr.Iterations != 1 {
+				t.Fatalf("Expected an IterationLimit after 1 iteration, got %v", err)
+			}
+			if (limitErr.Basis != nil) != (opts.Algorithm != InteriorPoint) {
+				t.Errorf("Expected a basis from the simplex only, got %+v", limitErr.Basis)
+			}
+
+			// The basis reached continues the solve
+			lp := parametricProblem()
+			opts.MaxIterations = 0
+			opts.WarmStart = limitErr.Basis
+			result, err := SolveWithOptions(context.Background(), lp, opts)
+			if err != nil {
+				t.Fatalf("SolveWithOptions() error = %v", err)
+			}
+			if math.Abs(result.ObjVar[2]-36) > 1e-6 {
+				t.Errorf("Expected the optimum 36, got %v", result.ObjVar)
+			}
+		})
+	}
+}
+
+func TestSolveWithOptions_Context(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := SolveWithOptions(canceled, parametricProblem(), SolveOptions{})
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Status != Canceled || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a Canceled LimitError, got %v", err)
+	}
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, err = SolveWithOptions(expired, parametricProblem(), SolveOptions{Algorithm: RevisedSimplex, Presolve: true})
+	if !errors.As(err, &limitErr) || limitErr.Status != TimeLimit || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a TimeLimit LimitError, got %v", err)
+	}
+	if limitErr.Basis != nil {
+		t.Errorf("Expected no basis for the presolved problem, got %+v", limitErr.Basis)
+	}
+
+	// A generous time limit does not get in the way
+	lp := parametricProblem()
+	if _, err := SolveWithOptions(context.Background(), lp, SolveOptions{TimeLimit: time.Minute}); err != nil {
+		t.Errorf("SolveWithOptions() error = %v", err)
+	}
+}
+
+func TestLimits_TimeLimit(t *testing.T) {
+	l := newLimits(context.Background(), SolveOptions{TimeLimit: time.Minute, MaxIterations: 5})
+	if err := l.step(); err != nil {
+		t.Fatalf("Expected the first iteration to run, got %v", err)
+	}
+	l.deadline = time.Now().Add(-time.Second)
+	err := l.step()
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Status != TimeLimit || limitErr.Iterations != 1 {
+		t.Errorf("Expected a TimeLimit after 1 iteration, got %v", err)
+	}
+	if got := err.Error(); got != "solve stopped after 1 iterations: TimeLimit" {
+		t.Errorf("Unexpected message %q", got)
+	}
+
+	var none *limits
+	if err := none.step(); err != nil {
+		t.Errorf("Expected nil limits never to stop, got %v", err)
+	}
+}
@@ -0,0 +1,336 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// luPivotThreshold is the smallest ratio between a pivot and the largest entry of its row. A smaller threshold
+// lets the Markowitz rule choose sparser pivots, a larger one keeps the factorization more stable.
+const luPivotThreshold = 0.1
+
+// luSearchLimit is the number of rows and columns the Markowitz search examines once it has found a pivot.
+const luSearchLimit = 4
+
+// LUFactorization holds the sparse factorization of a square matrix B computed by Gaussian elimination. Step k
+// eliminates column cols[k] with the pivot in row rows[k]: lower[k] holds the multipliers of that row subtracted
+// from the rows not yet eliminated, and upper[k] holds the pivot row at that step, pivot included. The pivots are
+// chosen with the Markowitz rule, so L and U stay about as sparse as B.
+type LUFactorization struct {
+	size   int
+	rows   []int                // pivot row of each step
+	cols   []int                // pivot column of each step
+	pivots []float64            // pivot of each step
+	lower  []model.SparseVector // multipliers of each step, by row
+	upper  []model.SparseVector // pivot row of each step, by column
+}
+
+// Factorize computes the LU factorization of the square matrix b.
+// It returns an error if the matrix is singular.
+func Factorize(b [][]float64) (*LUFactorization, error) {
+	columns := make([]model.SparseVector, len(b))
+	for k := range columns {
+		for i := range b {
+			if b[i][k] != 0 {
+				columns[k].Indices = append(columns[k].Indices, i)
+				columns[k].Values = append(columns[k].Values, b[i][k])
+			}
+		}
+	}
+	return FactorizeSparse(len(b), columns)
+}
+
+// FactorizeSparse computes the LU factorization of the square matrix of the given size stored by column,
+// without building it densely. It returns an error if the matrix is singular.
+func FactorizeSparse(size int, columns []model.SparseVector) (*LUFactorization, error) {
+	work := newLUWork(size, columns)
+	f := &LUFactorization{size: size}
+	for k := 0; k < size; k++ {
+		p, q, ok := work.findPivot()
+		if !ok {
+			return nil, fmt.Errorf("singular basis matrix")
+		}
+		pivot, lower, upper := work.eliminate(p, q)
+		f.rows = append(f.rows, p)
+		f.cols = append(f.cols, q)
+		f.pivots = append(f.pivots, pivot)
+		f.lower = append(f.lower, lower)
+		f.upper = append(f.upper, upper)
+	}
+	return f, nil
+}
+
+// Solve returns x such that B*x = rhs.
+func (f *LUFactorization) Solve(rhs []float64) []float64 {
+	w := make([]float64, f.size)
+	copy(w, rhs)
+
+	// Apply the eliminations of L
+	for k, p := range f.rows {
+		if w[p] == 0 {
+			continue
+		}
+		for n, i := range f.lower[k].Indices {
+			w[i] -= f.lower[k].Values[n] * w[p]
+		}
+	}
+
+	// Back substitution with U
+	x := make([]float64, f.size)
+	for k := f.size - 1; k >= 0; k-- {
+		sum := w[f.rows[k]]
+		for n, j := range f.upper[k].Indices {
+			if j != f.cols[k] {
+				sum -= f.upper[k].Values[n] * x[j]
+			}
+		}
+		x[f.cols[k]] = sum / f.pivots[k]
+	}
+	return x
+}
+
+// SolveTranspose returns x such that B^T*x = rhs.
+func (f *LUFactorization) SolveTranspose(rhs []float64) []float64 {
+	w := make([]float64, f.size)
+	copy(w, rhs)
+
+	// Forward substitution with U^T
+	x := make([]float64, f.size)
+	for k, q := range f.cols {
+		value := w[q] / f.pivots[k]
+		x[f.rows[k]] = value
+		if value == 0 {
+			continue
+		}
+		for n, j := range f.upper[k].Indices {
+			if j != q {
+				w[j] -= f.upper[k].Values[n] * value
+			}
+		}
+	}
+
+	// Undo the eliminations of L in reverse order
+	for k := f.size - 1; k >= 0; k-- {
+		x[f.rows[k]] -= f.lower[k].Dot(x)
+	}
+	return x
+}
+
+// luWork is the active submatrix during the factorization. Rows keep their entries, columns only the rows of
+// their entries, and both are linked in lists by number of entries for the Markowitz search.
+type luWork struct {
+	rowCols [][]int     // columns of the entries of each active row
+	rowVals [][]float64 // values of the entries of each active row
+	rowMax  []float64   // largest absolute entry of each active row
+	colRows [][]int     // rows of the entries of each active column
+
+	rowHead, rowNext, rowPrev []int // active rows linked by number of entries
+	colHead, colNext, colPrev []int // active columns linked by number of entries
+	position                  []int // scratch: index of each column in the row being updated, -1 if absent
+}
+
+func newLUWork(size int, columns []model.SparseVector) *luWork {
+	w := &luWork{
+		rowCols:  make([][]int, size),
+		rowVals:  make([][]float64, size),
+		rowMax:   make([]float64, size),
+		colRows:  make([][]int, size),
+		rowHead:  make([]int, size+1),
+		rowNext:  make([]int, size),
+		rowPrev:  make([]int, size),
+		colHead:  make([]int, size+1),
+		colNext:  make([]int, size),
+		colPrev:  make([]int, size),
+		position: make([]int, size),
+	}
+	for j, column := range columns {
+		for n, i := range column.Indices {
+			if column.Values[n] == 0 {
+				continue
+			}
+			w.rowCols[i] = append(w.rowCols[i], j)
+			w.rowVals[i] = append(w.rowVals[i], column.Values[n])
+			w.colRows[j] = append(w.colRows[j], i)
+		}
+	}
+	for count := range w.rowHead {
+		w.rowHead[count] = -1
+		w.colHead[count] = -1
+	}
+	for i := 0; i < size; i++ {
+		w.position[i] = -1
+		w.rowMax[i] = maxAbs(w.rowVals[i])
+		w.link(w.rowHead, w.rowNext, w.rowPrev, i, len(w.rowCols[i]))
+		w.link(w.colHead, w.colNext, w.colPrev, i, len(w.colRows[i]))
+	}
+	return w
+}
+
+// link adds the row or column to the list of those with count entries.
+func (w *luWork) link(head, next, prev []int, index, count int) {
+	next[index] = head[count]
+	prev[index] = -1
+	if head[count] >= 0 {
+		prev[head[count]] = index
+	}
+	head[count] = index
+}
+
+// unlink removes the row or column from the list of those with count entries.
+func (w *luWork) unlink(head, next, prev []int, index, count int) {
+	if prev[index] >= 0 {
+		next[prev[index]] = next[index]
+	} else {
+		head[count] = next[index]
+	}
+	if next[index] >= 0 {
+		prev[next[index]] = prev[index]
+	}
+}
+
+// value returns the entry of the active submatrix in row i and column j.
+func (w *luWork) value(i, j int) float64 {
+	for n, col := range w.rowCols[i] {
+		if col == j {
+			return w.rowVals[i][n]
+		}
+	}
+	return 0
+}
+
+// maxAbs returns the largest absolute value of the slice.
+func maxAbs(values []float64) float64 {
+	max := 0.0
+	for _, value := range values {
+		if math.Abs(value) > max {
+			max = math.Abs(value)
+		}
+	}
+	return max
+}
+
+// findPivot returns the entry with the smallest Markowitz cost (r-1)*(c-1), where r and c count the entries of
+// its row and column, among the entries at least luPivotThreshold times the largest of their row. Rows and
+// columns are searched by increasing number of entries, and the search stops once no entry left can cost less, or
+// after luSearchLimit more rows and columns.
+func (w *luWork) findPivot() (int, int, bool) {
+	epsilon := 1e-12
+
+	bestRow, bestCol, bestCost, bestValue := -1, -1, math.MaxInt, 0.0
+	consider := func(i, j, cost int, value float64) {
+		if cost > bestCost || (cost == bestCost && math.Abs(value) <= math.Abs(bestValue)) {
+			return
+		}
+		if math.Abs(value) >= epsilon && math.Abs(value) >= luPivotThreshold*w.rowMax[i] {
+			bestRow, bestCol, bestCost, bestValue = i, j, cost, value
+		}
+	}
+
+	searched := 0
+	for count := 1; count < len(w.colHead); count++ {
+		for j := w.colHead[count]; j >= 0; j = w.colNext[j] {
+			for _, i := range w.colRows[j] {
+				consider(i, j, (len(w.rowCols[i])-1)*(count-1), w.value(i, j))
+			}
+			if bestRow >= 0 {
+				searched++
+			}
+			if bestCost <= (count-1)*(count-1) || searched > luSearchLimit {
+				return bestRow, bestCol, true
+			}
+		}
+		for i := w.rowHead[count]; i >= 0; i = w.rowNext[i] {
+			for n, j := range w.rowCols[i] {
+				consider(i, j, (count-1)*(len(w.colRows[j])-1), w.rowVals[i][n])
+			}
+			if bestRow >= 0 {
+				searched++
+			}
+			if bestCost <= count*(count-1) || searched > luSearchLimit {
+				return bestRow, bestCol, true
+			}
+		}
+	}
+	return bestRow, bestCol, bestRow >= 0
+}
+
+// eliminate removes row p and column q from the active submatrix, subtracting multiples of row p from the other
+// rows of column q. It returns the pivot, the multipliers by row and row p by column.
+func (w *luWork) eliminate(p, q int) (float64, model.SparseVector, model.SparseVector) {
+	w.unlink(w.rowHead, w.rowNext, w.rowPrev, p, len(w.rowCols[p]))
+	w.unlink(w.colHead, w.colNext, w.colPrev, q, len(w.colRows[q]))
+
+	upper := model.SparseVector{Indices: w.rowCols[p], Values: w.rowVals[p]}
+	pivot := w.value(p, q)
+	w.rowCols[p], w.rowVals[p] = nil, nil
+
+	// The other columns of row p lose their entry in it and may gain fill below
+	for _, j := range upper.Indices {
+		if j != q {
+			w.unlink(w.colHead, w.colNext, w.colPrev, j, len(w.colRows[j]))
+			w.colRows[j] = removeIndex(w.colRows[j], p)
+		}
+	}
+
+	var lower model.SparseVector
+	for _, i := range w.colRows[q] {
+		if i == p {
+			continue
+		}
+		w.unlink(w.rowHead, w.rowNext, w.rowPrev, i, len(w.rowCols[i]))
+
+		// Drop the entry of column q and scatter the rest of row i
+		cols, vals := w.rowCols[i][:0], w.rowVals[i][:0]
+		factor := 0.0
+		for n, j := range w.rowCols[i] {
+			if j == q {
+				factor = w.rowVals[i][n] / pivot
+				continue
+			}
+			w.position[j] = len(cols)
+			cols = append(cols, j)
+			vals = append(vals, w.rowVals[i][n])
+		}
+		lower.Indices = append(lower.Indices, i)
+		lower.Values = append(lower.Values, factor)
+
+		for n, j := range upper.Indices {
+			if j == q {
+				continue
+			}
+			if w.position[j] >= 0 {
+				vals[w.position[j]] -= factor * upper.Values[n]
+				continue
+			}
+			cols = append(cols, j)
+			vals = append(vals, -factor*upper.Values[n])
+			w.colRows[j] = append(w.colRows[j], i)
+		}
+		for _, j := range cols {
+			w.position[j] = -1
+		}
+		w.rowCols[i], w.rowVals[i], w.rowMax[i] = cols, vals, maxAbs(vals)
+		w.link(w.rowHead, w.rowNext, w.rowPrev, i, len(cols))
+	}
+	w.colRows[q] = nil
+
+	for _, j := range upper.Indices {
+		if j != q {
+			w.link(w.colHead, w.colNext, w.colPrev, j, len(w.colRows[j]))
+		}
+	}
+	return pivot, lower, upper
+}
+
+// removeIndex removes the first occurrence of the value from the slice, without keeping the order.
+func removeIndex(indices []int, value int) []int {
+	for n, index := range indices {
+		if index == value {
+			indices[n] = indices[len(indices)-1]
+			return indices[:len(indices)-1]
+		}
+	}
+	return indices
+}
@@ -0,0 +1,103 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestFactorize(t *testing.T) {
+	b := [][]float64{
+		{0, 2, 1},
+		{1, 1, 0},
+		{3, 0, 4},
+	}
+
+	lu, err := Factorize(b)
+	if err != nil {
+		t.Fatalf("Factorize() error = %v", err)
+	}
+
+	rhs := []float64{5, 3, 11}
+	x := lu.Solve(rhs)
+	for i := range b {
+		if got := dot(b[i], x); !equalFloat64Slices([]float64{got}, []float64{rhs[i]}, 1e-9) {
+			t.Errorf("Expected row %d of B*x to be %v, got %v", i, rhs[i], got)
+		}
+	}
+
+	y := lu.SolveTranspose(rhs)
+	for j := range b {
+		got := 0.0
+		for i := range b {
+			got += b[i][j] * y[i]
+		}
+		if !equalFloat64Slices([]float64{got}, []float64{rhs[j]}, 1e-9) {
+			t.Errorf("Expected row %d of B^T*y to be %v, got %v", j, rhs[j], got)
+		}
+	}
+}
+
+func TestFactorize_Singular(t *testing.T) {
+	b := [][]float64{
+		{1, 2},
+		{2, 4},
+	}
+
+	_, err := Factorize(b)
+	if err == nil {
+		t.Errorf("Expected an error for a singular matrix, got nil")
+	}
+}
+
+func TestFactorizeSparse(t *testing.T) {
+	// Arrowhead matrix with a dense first row and column: eliminating in order fills it completely,
+	// while the Markowitz rule keeps the factors as sparse as the matrix
+	size := 200
+	columns := make([]model.SparseVector, size)
+	for j := range columns {
+		if j == 0 {
+			for i := 0; i < size; i++ {
+				columns[j].Set(i, 1)
+			}
+			columns[j].Set(0, float64(size))
+			continue
+		}
+		columns[j].Set(0, 1)
+		columns[j].Set(j, 4)
+	}
+
+	lu, err := FactorizeSparse(size, columns)
+	if err != nil {
+		t.Fatalf("FactorizeSparse() error = %v", err)
+	}
+	nonZeros := 0
+	for k := range lu.upper {
+		nonZeros += len(lu.upper[k].Indices) + len(lu.lower[k].Indices)
+	}
+	if nonZeros > 3*size {
+		t.Errorf("Expected at most %d non-zeros in L and U, got %d", 3*size, nonZeros)
+	}
+
+	rhs := make([]float64, size)
+	for i := range rhs {
+		rhs[i] = float64(i%7) - 3
+	}
+	x := lu.Solve(rhs)
+	product := make([]float64, size)
+	for j, column := range columns {
+		for p, i := range column.Indices {
+			product[i] += column.Values[p] * x[j]
+		}
+	}
+	if !equalFloat64Slices(product, rhs, 1e-9) {
+		t.Errorf("Expected B*x to be the right-hand side, got %v", product)
+	}
+
+	y := lu.SolveTranspose(rhs)
+	for j, column := range columns {
+		if got := column.Dot(y); !equalFloat64Slices([]float64{got}, []float64{rhs[j]}, 1e-9) {
+			t.Errorf("Expected row %d of B^T*y to be %v, got %v", j, rhs[j], got)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package solver
+
+import (
+	"time"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// Initialization selects how the solver finds a feasible starting basis
+// when the slack basis is infeasible.
+type Initialization int
+
+const (
+	TwoPhase Initialization = iota
+	BigM
+)
+
+// Algorithm selects the simplex variant used to solve the problem.
+type Algorithm int
+
+const (
+	Automatic Algorithm = iota // Revised simplex for large problems, the tableau primal simplex otherwise
+	PrimalSimplex
+	DualSimplex
+	RevisedSimplex
+	InteriorPoint
+)
+
+// ScalingMethod selects how SolveWithOptions rescales the rows and columns of the problem before solving it.
+type ScalingMethod int
+
+const (
+	NoScaling            ScalingMethod = iota
+	EquilibrationScaling               // Divide every row, then every column, by its largest coefficient
+	GeometricMeanScaling               // Divide by the geometric mean of the extreme coefficients, then equilibrate
+)
+
+// RevisedSimplexThreshold is the tableau size (rows x columns) above which Automatic uses the revised simplex.
+const RevisedSimplexThreshold = 1 << 20
+
+// DefaultBigMPenalty is the artificial variable penalty used when SolveOptions.BigMPenalty is not set.
+const DefaultBigMPenalty = 1e6
+
+// SolveOptions configures a call to SolveWithOptions.
+type SolveOptions struct {
+	Algorithm      Algorithm
+	Initialization Initialization // Only used by the primal simplex
+	BigMPenalty    float64        // Penalty M of the artificial variables in Big-M mode
+	Pricing        Pricing        // Entering variable rule of the tableau and revised primal simplex
+	WarmStart      *model.Basis   // Starting basis, such as the Basis of a previous solve, instead of the slack basis
+	Presolve       bool           // Reduce the problem with Presolve first; the warm start and the sensitivity are then lost
+	Scaling        ScalingMethod  // Rescale the rows and columns with Scale first; the results are unscaled afterwards
+	MaxIterations  int            // Stop with a *LimitError after this many iterations, 0 means no limit
+	TimeLimit      time.Duration  // Stop with a *LimitError after this much time, 0 means no limit
+	Trace          bool           // Record the iterations of the tableau simplex in Result.Trace, not RevisedSimplex or InteriorPoint
+
+	InteriorPointLog func(InteriorPointIteration) // Called after every interior-point iteration
+
+	trace *Trace // Trace recorded by SolveWithOptions when Trace is set
+}
+
+func (opts SolveOptions) bigMPenalty() float64 {
+	if opts.BigMPenalty <= 0 {
+		return DefaultBigMPenalty
+	}
+	return opts.BigMPenalty
+}
+
+// algorithmFor resolves Automatic into a concrete algorithm for the problem in slack form.
+func (opts SolveOptions) algorithmFor(lp *model.LinearProgram) Algorithm {
+	if opts.Algorithm != Automatic {
+		return opts.Algorithm
+	}
+	if opts.Initialization == TwoPhase && !opts.Trace && (lp.NbConstraints+1)*(lp.NbVariables+1) > RevisedSimplexThreshold {
+		return RevisedSimplex
+	}
+	return PrimalSimplex
+}
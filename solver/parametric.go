@@ -0,0 +1,327 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// ParametricSegment is an interval of the parameter over which one basis stays optimal. Within it the solution
+// and the objective value change linearly from Start to End.
+type ParametricSegment struct {
+	From, To   float64
+	Basis      *model.Basis
+	Start, End []float64 // Solutions at From and To: the values of the variables followed by the objective value
+}
+
+// ParametricResult is the optimal solution traced as a piecewise-linear function of the parameter.
+type ParametricResult struct {
+	Segments []ParametricSegment
+	// StoppedBy explains why the trace ends before the end of the interval: an *InfeasibleError past the last
+	// segment of ParametricRhs, or an *UnboundedError past the last segment of ParametricObjective. It is nil
+	// when the segments cover the whole interval. A trace that stops at `from` keeps the segment [from, from].
+	StoppedBy error
+}
+
+// Breakpoints returns the values of the parameter at which the optimal basis changes.
+func (r *ParametricResult) Breakpoints() []float64 {
+	var breakpoints []float64
+	for k := 1; k < len(r.Segments); k++ {
+		breakpoints = append(breakpoints, r.Segments[k].From)
+	}
+	return breakpoints
+}
+
+// SolutionAt returns the optimal solution for the parameter theta, interpolated within its segment, and false if
+// theta lies outside the traced interval.
+func (r *ParametricResult) SolutionAt(theta float64) ([]float64, bool) {
+	for _, segment := range r.Segments {
+		if theta < segment.From || theta > segment.To {
+			continue
+		}
+		t := 0.0
+		if segment.To > segment.From {
+			t = (theta - segment.From) / (segment.To - segment.From)
+		}
+		solution := make([]float64, len(segment.Start))
+		for j := range solution {
+			solution[j] = segment.Start[j] + t*(segment.End[j]-segment.Start[j])
+		}
+		return solution, true
+	}
+	return nil, false
+}
+
+// ObjectiveAt returns the optimal objective value for the parameter theta, and false if theta lies outside the
+// traced interval. It is continuous and piecewise linear, concave for ParametricRhs of a maximization and convex for
+// ParametricObjective of a maximization, the other way around for a minimization.
+func (r *ParametricResult) ObjectiveAt(theta float64) (float64, bool) {
+	solution, ok := r.SolutionAt(theta)
+	if !ok {
+		return 0, false
+	}
+	return solution[len(solution)-1], true
+}
+
+// maxParametricPivots bounds the number of pivots of a parametric trace, which only grows past it when the
+// pivots cycle through degenerate bases.
+func maxParametricPivots(table *SimplexTable) int {
+	return 50 * (len(table.data) + len(table.data[0]))
+}
+
+// ParametricRhs traces the optimal solution of the problem with the right-hand side Rhs + theta*direction, for
+// theta from `from` to `to`. It solves the problem at `from` on the tableau, then follows the basic solution,
+// which moves linearly with theta, until a basic variable reaches one of its bounds. There a dual simplex pivot
+// changes the basis, and a new segment starts. The trace stops early, with StoppedBy, when the problem becomes
+// infeasible. It only uses the simplex tableau and leaves the problem unchanged.
+func ParametricRhs(lp *model.LinearProgram, direction []float64, from, to float64, opts SolveOptions) (*ParametricResult, error) {
+	if len(direction) != lp.NbConstraints {
+		return nil, fmt.Errorf("expected %d direction components, got %d", lp.NbConstraints, len(direction))
+	}
+	if from > to {
+		return nil, fmt.Errorf("the interval [%g, %g] is empty", from, to)
+	}
+	epsilon := 1e-9
+
+	problem := lp.Clone()
+	for i := range problem.Rhs {
+		problem.Rhs[i] += from * direction[i]
+	}
+	table, err := optimalTableau(problem, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Direction of the RHS of the slack form, whose rows are the original constraints times their sign
+	rowDirection := make([]float64, problem.NbConstraints)
+	for i := range direction {
+		rows, signs := problem.OriginalRows(i)
+		for k, row := range rows {
+			rowDirection[row] = signs[k] * direction[i]
+		}
+	}
+
+	rhsCol := len(table.data[0]) - 1
+	first := table.firstSlack()
+	solution := func() []float64 {
+		return originalSolution(problem, table.ExtractSolution(problem), lp.Objective)
+	}
+
+	result := &ParametricResult{}
+	theta := from
+	for pivots := 0; ; pivots++ {
+		if pivots > maxParametricPivots(table) {
+			return nil, fmt.Errorf("the parametric trace did not terminate")
+		}
+
+		// The tableau rows combine the rows of the slack form with the entries under the slack columns,
+		// so the RHS of every row, objective included, changes at the rate of that combination of the direction
+		rates := make([]float64, len(table.data))
+		for i := range rates {
+			for r, d := range rowDirection {
+				rates[i] += table.data[i][first+r] * d
+			}
+		}
+
+		step, leavingRow := to-theta, -1
+		for i, col := range table.basicVariables {
+			value, rate := table.data[i][rhsCol], rates[i]
+			limit := math.Inf(1)
+			if rate < -epsilon {
+				limit = math.Max(value, 0) / -rate
+			} else if upper := table.upperBound(int(col)); rate > epsilon && !math.IsInf(upper, 1) {
+				limit = math.Max(upper-value, 0) / rate
+			}
+			if limit < step {
+				step, leavingRow = limit, i
+			}
+		}
+
+		// A step that ends within the tolerance of `to` ends there, so that the sum of the steps does not fall short
+		end := theta + step
+		if to-end <= epsilon {
+			step, end, leavingRow = to-theta, to, -1
+		}
+
+		start := solution()
+		for i := range table.data {
+			table.data[i][rhsCol] += step * rates[i]
+		}
+		segment := ParametricSegment{From: theta, To: end, Basis: table.Basis(), Start: start, End: solution()}
+		theta = extendSegments(result, segment, leavingRow == -1, epsilon)
+		if leavingRow == -1 {
+			return result, nil
+		}
+
+		// The leaving variable would cross its bound: replace it with the dual ratio test
+		if rates[leavingRow] > 0 {
+			table.complementBasic(leavingRow)
+		}
+		pivotCol := table.FindDualEnteringVariable(leavingRow)
+		if pivotCol == -1 {
+			result.Segments = stoppedAtFrom(result.Segments, segment, from)
+			result.StoppedBy = originalCertificate(problem, &InfeasibleError{Farkas: table.rowMultipliers(leavingRow)})
+			return result, nil
+		}
+		table.PerformPivot(leavingRow, pivotCol)
+		table.basicVariables[leavingRow] = float64(pivotCol)
+	}
+}
+
+// ParametricObjective traces the optimal solution of the problem with the objective coefficients
+// ObjCoeff + theta*direction, for theta from `from` to `to`. It solves the problem at `from` on the tableau, then
+// follows the reduced costs, which move linearly with theta, until one of them changes sign. There a primal simplex
+// pivot changes the basis, and a new segment starts. Within a segment the solution stays the same while the
+// objective value moves linearly. The trace stops early, with StoppedBy, when the problem becomes unbounded.
+// It only uses the simplex tableau and leaves the problem unchanged.
+func ParametricObjective(lp *model.LinearProgram, direction []float64, from, to float64, opts SolveOptions) (*ParametricResult, error) {
+	if len(direction) != lp.NbVariables {
+		return nil, fmt.Errorf("expected %d direction components, got %d", lp.NbVariables, len(direction))
+	}
+	if from > to {
+		return nil, fmt.Errorf("the interval [%g, %g] is empty", from, to)
+	}
+	epsilon := 1e-9
+
+	problem := lp.Clone()
+	for j := range problem.ObjCoeff {
+		problem.ObjCoeff[j] += from * direction[j]
+	}
+	table, err := optimalTableau(problem, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Direction of the maximized objective over the columns of the slack form
+	columnDirection := make([]float64, len(table.data[0])-1)
+	for j := range direction {
+		columns, signs := problem.OriginalColumns(j)
+		for k, col := range columns {
+			columnDirection[col] = signs[k] * direction[j]
+			if problem.NegatedObjective {
+				columnDirection[col] *= -1
+			}
+		}
+	}
+
+	objectiveRow := len(table.data) - 1
+	// solution returns the solution of the tableau with the objective value for the parameter theta
+	solution := func(theta float64) []float64 {
+		columnValues := table.ExtractSolution(problem)
+		values := problem.OriginalValues(columnValues[:len(columnValues)-1])
+		objective := lp.ObjConstant
+		for j, value := range values[:lp.NbVariables] {
+			objective += (lp.ObjCoeff[j] + theta*direction[j]) * value
+		}
+		return append(values[:lp.NbVariables], objective)
+	}
+
+	result := &ParametricResult{}
+	theta := from
+	for pivots := 0; ; pivots++ {
+		if pivots > maxParametricPivots(table) {
+			return nil, fmt.Errorf("the parametric trace did not terminate")
+		}
+
+		// The objective row holds c_B*B^-1*A_j - c_j, whose rate follows from the direction the same way. A
+		// complemented column x' = u - x has its cost negated.
+		cost := make([]float64, len(columnDirection))
+		for j, d := range columnDirection {
+			cost[j] = d
+			if table.atUpper[j] {
+				cost[j] = -d
+			}
+		}
+		rates := make([]float64, len(columnDirection))
+		for j := range rates {
+			rates[j] = -cost[j]
+			for i, col := range table.basicVariables {
+				rates[j] += cost[int(col)] * table.data[i][j]
+			}
+		}
+
+		step, enteringCol := to-theta, -1
+		for _, j := range table.nonbasicColumns() {
+			if rates[j] < -epsilon {
+				if limit := math.Max(table.data[objectiveRow][j], 0) / -rates[j]; limit < step {
+					step, enteringCol = limit, j
+				}
+			}
+		}
+
+		end := theta + step
+		if to-end <= epsilon {
+			step, end, enteringCol = to-theta, to, -1
+		}
+
+		for j, rate := range rates {
+			table.data[objectiveRow][j] += step * rate
+		}
+		segment := ParametricSegment{From: theta, To: end, Basis: table.Basis(), Start: solution(theta), End: solution(end)}
+		theta = extendSegments(result, segment, enteringCol == -1, epsilon)
+		if enteringCol == -1 {
+			return result, nil
+		}
+
+		// The entering column would get an improving reduced cost: move it into the basis like the primal simplex
+		pivotRow := table.FindLeavingVariable(enteringCol)
+		ratio := math.Inf(1)
+		if pivotRow != -1 {
+			ratio, _ = table.leavingRatio(pivotRow, enteringCol)
+		}
+		switch upper := table.upperBound(enteringCol); {
+		case !math.IsInf(upper, 1) && upper <= ratio:
+			table.complement(enteringCol)
+		case pivotRow == -1:
+			result.Segments = stoppedAtFrom(result.Segments, segment, from)
+			result.StoppedBy = originalCertificate(problem, table.unboundedError(enteringCol))
+			return result, nil
+		default:
+			table.pivot(pivotRow, enteringCol)
+		}
+	}
+}
+
+// extendSegments adds the segment of a step to the trace and returns the parameter the next step starts from.
+// A step of at most epsilon, unless it is the last one, gets no segment of its own: it extends the previous segment
+// so that the segments stay contiguous, or, before the first segment, leaves the start of the trace unchanged.
+func extendSegments(result *ParametricResult, segment ParametricSegment, last bool, epsilon float64) float64 {
+	segments := result.Segments
+	switch {
+	case segment.To-segment.From > epsilon || last && len(segments) == 0:
+		result.Segments = append(segments, segment)
+	case len(segments) > 0:
+		segments[len(segments)-1].To = segment.To
+		segments[len(segments)-1].End = segment.End
+	default:
+		return segment.From
+	}
+	return segment.To
+}
+
+// stoppedAtFrom returns the segments of a trace that stops, with the zero-width segment [from, from] when the
+// trace stops after degenerate steps only, so that the solution at `from` stays available.
+func stoppedAtFrom(segments []ParametricSegment, last ParametricSegment, from float64) []ParametricSegment {
+	if len(segments) > 0 {
+		return segments
+	}
+	last.From, last.To = from, from
+	last.End = last.Start
+	return append(segments, last)
+}
+
+// nonbasicColumns returns the nonbasic columns of the tableau, without the artificial ones.
+func (table *SimplexTable) nonbasicColumns() []int {
+	basic := make([]bool, len(table.data[0])-1)
+	for _, col := range table.basicVariables {
+		basic[int(col)] = true
+	}
+	var columns []int
+	for j := range basic {
+		if !basic[j] && !table.isArtificial(j) {
+			columns = append(columns, j)
+		}
+	}
+	return columns
+}
@@ -0,0 +1,334 @@
+package solver
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// parametricProblem builds maximize 3x + 5y subject to x <= 4, 2y <= 12, 3x + 2y <= 18, whose optimum is (2, 6).
+func parametricProblem() *model.LinearProgram {
+	return &model.LinearProgram{
+		NbConstraints: 3,
+		NbVariables:   2,
+		VariableNames: []string{"x", "y"},
+		Objective:     model.MAXIMIZE,
+		ObjCoeff:      []float64{3, 5},
+		Comparisons:   []model.Comparison{model.LE, model.LE, model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 0},
+			{0, 2},
+			{3, 2},
+		}),
+		Rhs: []float64{4, 12, 18},
+	}
+}
+
+func TestParametricRhs(t *testing.T) {
+	tests := []struct {
+		name        string
+		build       func() *model.LinearProgram
+		direction   []float64
+		from, to    float64
+		breakpoints []float64
+		objectives  map[float64]float64
+	}{
+		{
+			// The capacity 18 + theta of the third constraint: y grows until it reaches 6, then x until it reaches 4
+			name:        "Capacity",
+			build:       parametricProblem,
+			direction:   []float64{0, 0, 1},
+			from:        -18,
+			to:          20,
+			breakpoints: []float64{-6, 6},
+			objectives:  map[float64]float64{-18: 0, -12: 15, -6: 30, 0: 36, 6: 42, 20: 42},
+		},
+		{
+			// minimize 2x + 3y subject to x + y = 4 + theta, x <= 3: x fills the demand until its bound
+			name: "EqualityMinimize",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints:   1,
+					NbVariables:     2,
+					VariableNames:   []string{"x", "y"},
+					Objective:       model.MINIMIZE,
+					ObjCoeff:        []float64{2, 3},
+					Comparisons:     []model.Comparison{model.EQ},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}}),
+					Rhs:             []float64{4},
+					UpperBounds:     []float64{3, math.Inf(1)},
+				}
+			},
+			direction:   []float64{1},
+			from:        -4,
+			to:          2,
+			breakpoints: []float64{-1},
+			objectives:  map[float64]float64{-4: 0, -2: 4, -1: 6, 0: 9, 2: 15},
+		},
+	}
+
+	for _, tt := range tests {
+		for name, opts := range map[string]SolveOptions{"PrimalSimplex": {}, "BigM": {Initialization: BigM}} {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				lp := tt.build()
+				result, err := ParametricRhs(lp, tt.direction, tt.from, tt.to, opts)
+				if err != nil {
+					t.Fatalf("ParametricRhs() error = %v", err)
+				}
+				if result.StoppedBy != nil {
+					t.Errorf("Expected the whole interval to be traced, stopped by %v", result.StoppedBy)
+				}
+				if !equalFloat64Slices(result.Breakpoints(), tt.breakpoints, 1e-9) {
+					t.Errorf("Expected breakpoints %v, got %v", tt.breakpoints, result.Breakpoints())
+				}
+				for theta, expected := range tt.objectives {
+					if got, ok := result.ObjectiveAt(theta); !ok || math.Abs(got-expected) > 1e-9 {
+						t.Errorf("Expected the objective %v at %v, got %v", expected, theta, got)
+					}
+				}
+
+				// Every segment matches an independent solve at its ends, with the basis reported for it
+				for _, segment := range result.Segments {
+					for _, theta := range []float64{segment.From, segment.To} {
+						solved := tt.build()
+						for i := range solved.Rhs {
+							solved.Rhs[i] += theta * tt.direction[i]
+						}
+						solvedResult, err := Solve(solved)
+						if err != nil {
+							t.Fatalf("Solve() error = %v", err)
+						}
+						got, _ := result.ObjectiveAt(theta)
+						if math.Abs(got-solvedResult.ObjVar[len(solvedResult.ObjVar)-1]) > 1e-9 {
+							t.Errorf("Expected the objective %v at %v, got %v", solvedResult.ObjVar, theta, got)
+						}
+					}
+					if segment.Basis == nil {
+						t.Errorf("Expected the basis of segment [%v, %v]", segment.From, segment.To)
+					}
+				}
+				if !reflect.DeepEqual(lp, tt.build()) {
+					t.Errorf("Expected ParametricRhs() to leave the problem unchanged")
+				}
+			})
+		}
+	}
+}
+
+func TestParametricRhs_Infeasible(t *testing.T) {
+	// Lowering the capacity 18 - theta of 3x + 2y below the demand y >= 2 leaves no solution past theta = 14
+	lp := parametricProblem()
+	lp.NbConstraints = 4
+	lp.Comparisons = append(lp.Comparisons, model.BE)
+	lp.ConstraintCoeff = model.NewSparseMatrixFromDense([][]float64{{1, 0}, {0, 2}, {3, 2}, {0, 1}})
+	lp.Rhs = append(lp.Rhs, 2)
+
+	result, err := ParametricRhs(lp, []float64{0, 0, -1, 0}, 0, 30, SolveOptions{})
+	if err != nil {
+		t.Fatalf("ParametricRhs() error = %v", err)
+	}
+	var infeasible *InfeasibleError
+	if !errors.As(result.StoppedBy, &infeasible) {
+		t.Fatalf("Expected the trace to stop on an InfeasibleError, got %v", result.StoppedBy)
+	}
+	last := result.Segments[len(result.Segments)-1]
+	if math.Abs(last.To-14) > 1e-9 || !equalFloat64Slices(last.End, []float64{0, 2, 10}, 1e-9) {
+		t.Errorf("Expected the trace to end at 14 with [0 2 10], got %v with %v", last.To, last.End)
+	}
+	if _, ok := result.ObjectiveAt(20); ok {
+		t.Errorf("Expected no objective past the end of the trace")
+	}
+
+	// The certificate holds for the problem just past the end of the trace
+	past := lp.Clone()
+	past.Rhs[2] -= 15
+	if err := CheckCertificate(past, result.StoppedBy); err != nil {
+		t.Errorf("CheckCertificate() error = %v", err)
+	}
+}
+
+func TestParametricObjective(t *testing.T) {
+	// The profit 3 + theta of x: the optimum moves from (2, 6) to (4, 3) when 3 + theta exceeds 7.5
+	lp := parametricProblem()
+	result, err := ParametricObjective(lp, []float64{1, 0}, 0, 10, SolveOptions{})
+	if err != nil {
+		t.Fatalf("ParametricObjective() error = %v", err)
+	}
+	if !equalFloat64Slices(result.Breakpoints(), []float64{4.5}, 1e-9) || result.StoppedBy != nil {
+		t.Fatalf("Expected the breakpoint 4.5, got %v stopped by %v", result.Breakpoints(), result.StoppedBy)
+	}
+	first, second := result.Segments[0], result.Segments[1]
+	if !equalFloat64Slices(first.Start, []float64{2, 6, 36}, 1e-9) || !equalFloat64Slices(first.End, []float64{2, 6, 45}, 1e-9) {
+		t.Errorf("Expected (2, 6) from 36 to 45, got %v to %v", first.Start, first.End)
+	}
+	if !equalFloat64Slices(second.Start, []float64{4, 3, 45}, 1e-9) || !equalFloat64Slices(second.End, []float64{4, 3, 67}, 1e-9) {
+		t.Errorf("Expected (4, 3) from 45 to 67, got %v to %v", second.Start, second.End)
+	}
+	if !reflect.DeepEqual(lp, parametricProblem()) {
+		t.Errorf("Expected ParametricObjective() to leave the problem unchanged")
+	}
+
+	// minimize (1 - theta)x - y subject to -x + y <= 1, y <= 3: x becomes profitable past theta = 1, without limit
+	unbounded := &model.LinearProgram{
+		NbConstraints: 2,
+		NbVariables:   2,
+		VariableNames: []string{"x", "y"},
+		Objective:     model.MINIMIZE,
+		ObjCoeff:      []float64{1, -1},
+		Comparisons:   []model.Comparison{model.LE, model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{-1, 1},
+			{0, 1},
+		}),
+		Rhs: []float64{1, 3},
+	}
+	result, err = ParametricObjective(unbounded, []float64{-1, 0}, 0, 3, SolveOptions{})
+	if err != nil {
+		t.Fatalf("ParametricObjective() error = %v", err)
+	}
+	var unboundedErr *UnboundedError
+	if !errors.As(result.StoppedBy, &unboundedErr) {
+		t.Fatalf("Expected the trace to stop on an UnboundedError, got %v", result.StoppedBy)
+	}
+	if objective, ok := result.ObjectiveAt(1); !ok || math.Abs(objective+3) > 1e-9 || result.Segments[len(result.Segments)-1].To != 1 {
+		t.Errorf("Expected the trace to end at 1 with the objective -3, got %+v", result.Segments)
+	}
+
+	past := unbounded.Clone()
+	past.ObjCoeff[0] = -0.5
+	if err := CheckCertificate(past, result.StoppedBy); err != nil {
+		t.Errorf("CheckCertificate() error = %v", err)
+	}
+}
+
+func TestParametric_EndsOfInterval(t *testing.T) {
+	// Steps of 0.1 * (18 + theta) do not add up to the end of the interval in floating point
+	result, err := ParametricRhs(parametricProblem(), []float64{0, 0, 0.1}, -18, 20.3, SolveOptions{})
+	if err != nil {
+		t.Fatalf("ParametricRhs() error = %v", err)
+	}
+	if last := result.Segments[len(result.Segments)-1]; last.To != 20.3 || result.StoppedBy != nil {
+		t.Errorf("Expected the trace to end at 20.3, got %v stopped by %v", last.To, result.StoppedBy)
+	}
+	if objective, ok := result.ObjectiveAt(20.3); !ok || math.Abs(objective-38.03) > 1e-9 {
+		t.Errorf("Expected the objective 38.03 at 20.3, got %v", objective)
+	}
+
+	result, err = ParametricObjective(parametricProblem(), []float64{0.1, -0.1}, -18, 0.7, SolveOptions{})
+	if err != nil {
+		t.Fatalf("ParametricObjective() error = %v", err)
+	}
+	if last := result.Segments[len(result.Segments)-1]; last.To != 0.7 || result.StoppedBy != nil {
+		t.Errorf("Expected the trace to end at 0.7, got %v stopped by %v", last.To, result.StoppedBy)
+	}
+	if _, ok := result.ObjectiveAt(0.7); !ok {
+		t.Errorf("Expected the objective at 0.7")
+	}
+
+	// At theta = 14 the capacity 18 - theta of 3x + 2y just meets the demand y >= 2: the trace stops at once
+	lp := parametricProblem()
+	lp.NbConstraints = 4
+	lp.Comparisons = append(lp.Comparisons, model.BE)
+	lp.ConstraintCoeff = model.NewSparseMatrixFromDense([][]float64{{1, 0}, {0, 2}, {3, 2}, {0, 1}})
+	lp.Rhs = append(lp.Rhs, 2)
+	result, err = ParametricRhs(lp, []float64{0, 0, -1, 0}, 14, 30, SolveOptions{})
+	if err != nil {
+		t.Fatalf("ParametricRhs() error = %v", err)
+	}
+	if !errors.Is(result.StoppedBy, ErrInfeasible) || len(result.Segments) != 1 || result.Segments[0].To != 14 {
+		t.Fatalf("Expected the segment [14, 14] and an InfeasibleError, got %+v stopped by %v", result.Segments, result.StoppedBy)
+	}
+	if solution, ok := result.SolutionAt(14); !ok || !equalFloat64Slices(solution, []float64{0, 2, 10}, 1e-9) {
+		t.Errorf("Expected [0 2 10] at 14, got %v", solution)
+	}
+
+	// minimize (1 - theta)x - y subject to -x + y <= 1, y <= 3 is unbounded right past theta = 1
+	unbounded := &model.LinearProgram{
+		NbConstraints:   2,
+		NbVariables:     2,
+		VariableNames:   []string{"x", "y"},
+		Objective:       model.MINIMIZE,
+		ObjCoeff:        []float64{1, -1},
+		Comparisons:     []model.Comparison{model.LE, model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{-1, 1}, {0, 1}}),
+		Rhs:             []float64{1, 3},
+	}
+	result, err = ParametricObjective(unbounded, []float64{-1, 0}, 1, 3, SolveOptions{})
+	if err != nil {
+		t.Fatalf("ParametricObjective() error = %v", err)
+	}
+	if !errors.Is(result.StoppedBy, ErrUnbounded) || len(result.Segments) != 1 || result.Segments[0].To != 1 {
+		t.Fatalf("Expected the segment [1, 1] and an UnboundedError, got %+v stopped by %v", result.Segments, result.StoppedBy)
+	}
+	if objective, ok := result.ObjectiveAt(1); !ok || math.Abs(objective+3) > 1e-9 {
+		t.Errorf("Expected the objective -3 at 1, got %v", objective)
+	}
+}
+
+func TestParametric_ContiguousSegments(t *testing.T) {
+	// Two basic variables reach zero at theta = 3.5, y already sits at zero: the degenerate steps there are
+	// too short for a segment of their own, and the segments around them must still meet
+	lp := &model.LinearProgram{
+		NbConstraints: 3,
+		NbVariables:   3,
+		Objective:     model.MAXIMIZE,
+		ObjCoeff:      []float64{2, 2, 2},
+		Comparisons:   []model.Comparison{model.LE, model.LE, model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 1, 0},
+			{1, 1, 2},
+			{2, 3, 3},
+		}),
+		Rhs: []float64{4.2, 2.1, 2.1},
+	}
+	rhsResult, err := ParametricRhs(lp, []float64{-0.8999999999999999, -0.3, 0}, 0, 10, SolveOptions{})
+	if err != nil {
+		t.Fatalf("ParametricRhs() error = %v", err)
+	}
+
+	// The reduced costs of x and y reach zero together at theta = 5/3
+	lp = &model.LinearProgram{
+		NbConstraints: 3,
+		NbVariables:   2,
+		Objective:     model.MAXIMIZE,
+		ObjCoeff:      []float64{1, 1},
+		Comparisons:   []model.Comparison{model.LE, model.LE, model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{2, 3},
+			{1, 3},
+			{2, 1},
+		}),
+		Rhs: []float64{4.8999999999999995, 4.8999999999999995, 2.1},
+	}
+	objectiveResult, err := ParametricObjective(lp, []float64{-0.6, -0.6}, 0, 10, SolveOptions{})
+	if err != nil {
+		t.Fatalf("ParametricObjective() error = %v", err)
+	}
+
+	for name, result := range map[string]*ParametricResult{"Rhs": rhsResult, "Objective": objectiveResult} {
+		segments := result.Segments
+		if len(segments) < 2 || segments[0].From != 0 {
+			t.Fatalf("%s: expected several segments from 0, got %+v", name, segments)
+		}
+		for k := 0; k+1 < len(segments); k++ {
+			if segments[k].To != segments[k+1].From {
+				t.Errorf("%s: expected segment %d to end where the next one starts, got %v and %v", name, k, segments[k].To, segments[k+1].From)
+			}
+			if _, ok := result.SolutionAt(segments[k].To); !ok {
+				t.Errorf("%s: expected a solution at %v", name, segments[k].To)
+			}
+		}
+	}
+}
+
+func TestParametric_InvalidArguments(t *testing.T) {
+	if _, err := ParametricRhs(parametricProblem(), []float64{1}, 0, 1, SolveOptions{}); err == nil {
+		t.Errorf("Expected an error for a direction of the wrong length")
+	}
+	if _, err := ParametricObjective(parametricProblem(), []float64{1, 0}, 1, 0, SolveOptions{}); err == nil {
+		t.Errorf("Expected an error for an empty interval")
+	}
+}
@@ -0,0 +1,610 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// maxPresolvePasses bounds the number of passes of Presolve over the problem, which only keep finding reductions
+// past it when the bounds tighten by ever smaller steps.
+const maxPresolvePasses = 20
+
+// PresolveStats counts the reductions made by Presolve.
+type PresolveStats struct {
+	RemovedRows     int // Empty, singleton, duplicate and redundant constraints
+	RemovedColumns  int // Fixed variables, including those fixed by a dominated column or a singleton equality
+	TightenedBounds int // Variable bounds tightened by a singleton row or by the activity of a constraint
+}
+
+// impliedBound records that a constraint tightened a bound of a variable: coeff*x_col on the row implied the
+// upper or lower bound value. Postsolve undoes these entries in reverse order.
+type impliedBound struct {
+	row, col int
+	coeff    float64
+	value    float64
+	upper    bool
+}
+
+// Postsolve maps the solution of a problem reduced by Presolve back to the original problem.
+type Postsolve struct {
+	Stats    PresolveStats
+	original *model.LinearProgram
+	columns  []int           // Original variable of each column of the reduced problem
+	rows     []int           // Original constraint of each row of the reduced problem
+	fixed    map[int]float64 // Value of each removed variable
+	stack    []impliedBound  // Bounds implied by the constraints, in the order presolve tightened them
+}
+
+// presolver holds the problem while Presolve reduces it. Rows and columns are only deactivated, never renumbered,
+// and the constraint matrix itself never changes: fixing a variable moves its contribution into the RHS.
+type presolver struct {
+	lp           *model.LinearProgram
+	columns      *model.SparseMatrix // Transpose of the constraint matrix
+	rhs          []float64
+	lower, upper []float64
+	rowActive    []bool
+	colActive    []bool
+	postsolve    *Postsolve
+}
+
+// Presolve returns a reduced copy of the problem, which has the same optimal objective value, together with the
+// Postsolve that maps its solution back. It repeats, until nothing changes:
+//   - removing empty rows, and turning singleton rows into bounds on their variable;
+//   - removing fixed variables, whose lower and upper bounds are equal, by moving them into the RHS;
+//   - fixing dominated columns, which can only hurt the objective and the constraints when they move away from
+//     one of their bounds, at that bound;
+//   - removing duplicate rows, which are multiples of another row, keeping the tightest one;
+//   - tightening the bounds of the variables from the activity range of each constraint, and removing the
+//     constraints that the bounds alone already satisfy.
+//
+// The problem must not be converted, and is left unchanged. Presolve returns an *InfeasibleError without a
+// certificate when a reduction proves the problem infeasible.
+func Presolve(lp *model.LinearProgram) (*model.LinearProgram, *Postsolve, error) {
+	if lp.State != model.Undefined {
+		return nil, nil, fmt.Errorf("presolve needs a problem that is not converted")
+	}
+	if err := checkBounds(lp); err != nil {
+		return nil, nil, err
+	}
+
+	p := &presolver{
+		lp:        lp,
+		columns:   lp.ConstraintCoeff.Transpose(),
+		rhs:       append([]float64(nil), lp.Rhs...),
+		lower:     make([]float64, lp.NbVariables),
+		upper:     make([]float64, lp.NbVariables),
+		rowActive: make([]bool, lp.NbConstraints),
+		colActive: make([]bool, lp.NbVariables),
+		postsolve: &Postsolve{original: lp, fixed: make(map[int]float64)},
+	}
+	for j := range p.colActive {
+		p.lower[j], p.upper[j] = lp.Bounds(j)
+		p.colActive[j] = true
+	}
+	for i := range p.rowActive {
+		p.rowActive[i] = true
+	}
+
+	reductions := []func() (bool, error){
+		p.reduceRows, p.removeFixedColumns, p.fixDominatedColumns, p.removeDuplicateRows, p.tightenBounds,
+	}
+	changed := true
+	for pass := 0; changed && pass < maxPresolvePasses; pass++ {
+		changed = false
+		for _, reduce := range reductions {
+			reduced, err := reduce()
+			if err != nil {
+				return nil, nil, err
+			}
+			changed = changed || reduced
+		}
+	}
+	return p.reducedProblem(), p.postsolve, nil
+}
+
+// rowEntries returns the nonzero coefficients of the row on the active columns.
+func (p *presolver) rowEntries(i int) ([]int, []float64) {
+	var cols []int
+	var coeffs []float64
+	row := p.lp.ConstraintCoeff.Rows[i]
+	for k, j := range row.Indices {
+		if p.colActive[j] && row.Values[k] != 0 {
+			cols = append(cols, j)
+			coeffs = append(coeffs, row.Values[k])
+		}
+	}
+	return cols, coeffs
+}
+
+// hasUpperSide reports whether the constraint bounds its left-hand side from above.
+func hasUpperSide(c model.Comparison) bool {
+	return c == model.LE || c == model.LO || c == model.EQ
+}
+
+// hasLowerSide reports whether the constraint bounds its left-hand side from below.
+func hasLowerSide(c model.Comparison) bool {
+	return c == model.BE || c == model.BI || c == model.EQ
+}
+
+// presolveTolerance is the absolute tolerance of the comparisons with the value.
+func presolveTolerance(value float64) float64 {
+	return 1e-9 * (1 + math.Abs(value))
+}
+
+// removeRow deactivates a constraint, whose dual value is zero unless a bound it implied is active.
+func (p *presolver) removeRow(i int) {
+	p.rowActive[i] = false
+	p.postsolve.Stats.RemovedRows++
+}
+
+// setUpper tightens the upper bound of the column to the value implied by coeff*x_col on the row, and returns
+// an error when it crosses the lower bound.
+func (p *presolver) setUpper(row, col int, coeff, value float64) error {
+	if value < p.lower[col]-presolveTolerance(value) {
+		return &InfeasibleError{}
+	}
+	value = math.Max(value, p.lower[col])
+	p.upper[col] = value
+	p.postsolve.stack = append(p.postsolve.stack, impliedBound{row: row, col: col, coeff: coeff, value: value, upper: true})
+	p.postsolve.Stats.TightenedBounds++
+	return nil
+}
+
+// setLower tightens the lower bound of the column, like setUpper.
+func (p *presolver) setLower(row, col int, coeff, value float64) error {
+	if value > p.upper[col]+presolveTolerance(value) {
+		return &InfeasibleError{}
+	}
+	value = math.Min(value, p.upper[col])
+	p.lower[col] = value
+	p.postsolve.stack = append(p.postsolve.stack, impliedBound{row: row, col: col, coeff: coeff, value: value})
+	p.postsolve.Stats.TightenedBounds++
+	return nil
+}
+
+// reduceRows removes the empty rows, which must hold on their own, and turns the singleton rows a*x <= b,
+// a*x >= b and a*x = b into bounds on x.
+func (p *presolver) reduceRows() (bool, error) {
+	changed := false
+	for i, active := range p.rowActive {
+		if !active {
+			continue
+		}
+		cols, coeffs := p.rowEntries(i)
+		comparison, rhs := p.lp.Comparisons[i], p.rhs[i]
+		switch len(cols) {
+		case 0:
+			if hasUpperSide(comparison) && rhs < -presolveTolerance(rhs) || hasLowerSide(comparison) && rhs > presolveTolerance(rhs) {
+				return false, &InfeasibleError{}
+			}
+		case 1:
+			col, coeff := cols[0], coeffs[0]
+			bound := rhs / coeff
+			// Dividing by a negative coefficient turns the side of the constraint around
+			upperSide, lowerSide := hasUpperSide(comparison), hasLowerSide(comparison)
+			if coeff < 0 {
+				upperSide, lowerSide = lowerSide, upperSide
+			}
+			if upperSide && bound < p.upper[col] {
+				if err := p.setUpper(i, col, coeff, bound); err != nil {
+					return false, err
+				}
+			}
+			if lowerSide && bound > p.lower[col] {
+				if err := p.setLower(i, col, coeff, bound); err != nil {
+					return false, err
+				}
+			}
+		default:
+			continue
+		}
+		p.removeRow(i)
+		changed = true
+	}
+	return changed, nil
+}
+
+// removeFixedColumns removes the variables whose bounds are equal, moving their contribution into the RHS.
+func (p *presolver) removeFixedColumns() (bool, error) {
+	changed := false
+	for j, active := range p.colActive {
+		if !active || math.IsInf(p.lower[j], 0) || p.upper[j]-p.lower[j] > presolveTolerance(p.lower[j]) {
+			continue
+		}
+		value := p.lower[j]
+		column := p.columns.Rows[j]
+		for k, i := range column.Indices {
+			p.rhs[i] -= column.Values[k] * value
+		}
+		p.colActive[j] = false
+		p.postsolve.fixed[j] = value
+		p.postsolve.Stats.RemovedColumns++
+		changed = true
+	}
+	return changed, nil
+}
+
+// fixDominatedColumns fixes the variables that can move towards one of their bounds without hurting the objective
+// or any constraint at that bound. The next pass then removes them as fixed variables. An empty column is the
+// simplest case.
+func (p *presolver) fixDominatedColumns() (bool, error) {
+	sense := 1.0
+	if p.lp.Objective == model.MINIMIZE {
+		sense = -1
+	}
+
+	changed := false
+	for j, active := range p.colActive {
+		if !active || p.upper[j] == p.lower[j] {
+			continue
+		}
+		// Whether decreasing or increasing x never tightens a constraint
+		decreases, increases := true, true
+		column := p.columns.Rows[j]
+		for k, i := range column.Indices {
+			coeff := column.Values[k]
+			if !p.rowActive[i] || coeff == 0 {
+				continue
+			}
+			comparison := p.lp.Comparisons[i]
+			if hasUpperSide(comparison) && coeff < 0 || hasLowerSide(comparison) && coeff > 0 {
+				decreases = false
+			}
+			if hasUpperSide(comparison) && coeff > 0 || hasLowerSide(comparison) && coeff < 0 {
+				increases = false
+			}
+		}
+
+		cost := sense * p.lp.ObjCoeff[j]
+		switch {
+		case decreases && cost <= 0 && !math.IsInf(p.lower[j], -1):
+			p.upper[j] = p.lower[j]
+		case increases && cost >= 0 && !math.IsInf(p.upper[j], 1):
+			p.lower[j] = p.upper[j]
+		default:
+			continue
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// removeDuplicateRows removes the rows that are a multiple of an earlier row on the active columns. Two
+// inequalities on the same side keep the tighter one, an equation absorbs the inequalities it satisfies, and
+// contradicting rows make the problem infeasible.
+func (p *presolver) removeDuplicateRows() (bool, error) {
+	changed := false
+	representatives := make(map[string]int)
+	for i, active := range p.rowActive {
+		if !active {
+			continue
+		}
+		cols, coeffs := p.rowEntries(i)
+		if len(cols) < 2 {
+			continue
+		}
+		// Rows are compared scaled to a first coefficient of one
+		normalized := make([]float64, len(coeffs))
+		for k, coeff := range coeffs {
+			normalized[k] = coeff / coeffs[0]
+		}
+		key := fmt.Sprint(cols, roundedValues(normalized))
+		first, ok := representatives[key]
+		if !ok {
+			representatives[key] = i
+			continue
+		}
+
+		// Scale the row onto the representative, which turns its side around for a negative factor
+		_, firstCoeffs := p.rowEntries(first)
+		factor := coeffs[0] / firstCoeffs[0]
+		if !sameRow(firstCoeffs, coeffs, factor) {
+			continue
+		}
+		rhs := p.rhs[i] / factor
+		upperSide, lowerSide := hasUpperSide(p.lp.Comparisons[i]), hasLowerSide(p.lp.Comparisons[i])
+		if factor < 0 {
+			upperSide, lowerSide = lowerSide, upperSide
+		}
+		firstRhs := p.rhs[first]
+		firstUpper, firstLower := hasUpperSide(p.lp.Comparisons[first]), hasLowerSide(p.lp.Comparisons[first])
+		tolerance := presolveTolerance(firstRhs)
+
+		// Infeasible when one row bounds the activity from above below where the other bounds it from below
+		if firstUpper && lowerSide && rhs > firstRhs+tolerance || firstLower && upperSide && rhs < firstRhs-tolerance {
+			return false, &InfeasibleError{}
+		}
+
+		drop := -1
+		switch {
+		case firstUpper && firstLower:
+			drop = i // The equation implies the row
+		case upperSide && lowerSide:
+			drop = first // The row is an equation, which implies the representative
+		case firstUpper && upperSide:
+			drop = i
+			if rhs < firstRhs {
+				drop = first
+			}
+		case firstLower && lowerSide:
+			drop = i
+			if rhs > firstRhs {
+				drop = first
+			}
+		}
+		if drop == -1 {
+			continue // Two sides of a range, which both stay
+		}
+		if drop == first {
+			representatives[key] = i
+		}
+		p.removeRow(drop)
+		changed = true
+	}
+	return changed, nil
+}
+
+// sameRow reports whether the coefficients equal the first ones times the factor.
+func sameRow(first, coeffs []float64, factor float64) bool {
+	if len(first) != len(coeffs) {
+		return false
+	}
+	for k := range first {
+		if math.Abs(coeffs[k]-factor*first[k]) > presolveTolerance(coeffs[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// activity returns the finite part of the smallest and largest value of the row over the bounds, and how many
+// of its terms are unbounded below and above.
+func (p *presolver) activity(cols []int, coeffs []float64) (minActivity, maxActivity float64, minInfinite, maxInfinite int) {
+	for k, j := range cols {
+		low, high := p.contributions(j, coeffs[k])
+		if math.IsInf(low, -1) {
+			minInfinite++
+		} else {
+			minActivity += low
+		}
+		if math.IsInf(high, 1) {
+			maxInfinite++
+		} else {
+			maxActivity += high
+		}
+	}
+	return minActivity, maxActivity, minInfinite, maxInfinite
+}
+
+// contributions returns the smallest and largest value of coeff*x_j over the bounds of x_j.
+func (p *presolver) contributions(j int, coeff float64) (float64, float64) {
+	if coeff > 0 {
+		return coeff * p.lower[j], coeff * p.upper[j]
+	}
+	return coeff * p.upper[j], coeff * p.lower[j]
+}
+
+// residual returns the activity of the row without the term of one variable, from the finite part of the activity
+// and its count of infinite terms, and false when another term is infinite.
+func residual(activity float64, infinite int, contribution float64) (float64, bool) {
+	switch {
+	case infinite == 0:
+		return activity - contribution, true
+	case infinite == 1 && math.IsInf(contribution, 0):
+		return activity, true
+	}
+	return 0, false
+}
+
+// tightenBounds derives bounds on every variable of a row from the activity of its other terms: on a row
+// sum a_k*x_k <= b, a_j*x_j <= b - min(sum of the other terms). A row whose activity range already satisfies it
+// is removed, and one whose activity range cannot reach it makes the problem infeasible. Bounds only tighten when
+// they improve noticeably, so that the passes terminate.
+func (p *presolver) tightenBounds() (bool, error) {
+	changed := false
+	for i, active := range p.rowActive {
+		if !active {
+			continue
+		}
+		cols, coeffs := p.rowEntries(i)
+		comparison, rhs := p.lp.Comparisons[i], p.rhs[i]
+		upperSide, lowerSide := hasUpperSide(comparison), hasLowerSide(comparison)
+		minActivity, maxActivity, minInfinite, maxInfinite := p.activity(cols, coeffs)
+		tolerance := presolveTolerance(rhs)
+
+		if upperSide && minInfinite == 0 && minActivity > rhs+tolerance ||
+			lowerSide && maxInfinite == 0 && maxActivity < rhs-tolerance {
+			return false, &InfeasibleError{}
+		}
+		redundant := (!upperSide || maxInfinite == 0 && maxActivity <= rhs+tolerance) &&
+			(!lowerSide || minInfinite == 0 && minActivity >= rhs-tolerance)
+		if redundant && !(upperSide && lowerSide) {
+			p.removeRow(i)
+			changed = true
+			continue
+		}
+
+		for k, j := range cols {
+			tightened := false
+			coeff := coeffs[k]
+			if math.Abs(coeff) < 1e-9 {
+				continue // Dividing by a tiny coefficient would give a meaningless bound
+			}
+			low, high := p.contributions(j, coeff)
+			// The term a_j*x_j lies between the RHS minus the largest and minus the smallest other activity
+			termUpper, termLower := math.Inf(1), math.Inf(-1)
+			if rest, ok := residual(minActivity, minInfinite, low); upperSide && ok {
+				termUpper = rhs - rest
+			}
+			if rest, ok := residual(maxActivity, maxInfinite, high); lowerSide && ok {
+				termLower = rhs - rest
+			}
+			upper, lower := termUpper/coeff, termLower/coeff
+			if coeff < 0 {
+				upper, lower = lower, upper
+			}
+
+			if !math.IsInf(upper, 0) && upper < p.upper[j]-1e-6*(1+math.Abs(upper)) {
+				if err := p.setUpper(i, j, coeff, upper); err != nil {
+					return false, err
+				}
+				tightened = true
+			}
+			if !math.IsInf(lower, 0) && lower > p.lower[j]+1e-6*(1+math.Abs(lower)) {
+				if err := p.setLower(i, j, coeff, lower); err != nil {
+					return false, err
+				}
+				tightened = true
+			}
+			if tightened {
+				changed = true
+				// The other bounds of the row were computed with the old bounds of this variable
+				minActivity, maxActivity, minInfinite, maxInfinite = p.activity(cols, coeffs)
+			}
+		}
+	}
+	return changed, nil
+}
+
+// reducedProblem builds the problem over the active rows and columns, with the tightened bounds.
+func (p *presolver) reducedProblem() *model.LinearProgram {
+	lp := p.lp
+	ps := p.postsolve
+	ps.columns, ps.rows = nil, nil
+
+	newIndex := make([]int, lp.NbVariables)
+	objConstant := lp.ObjConstant
+	for j, active := range p.colActive {
+		if active {
+			newIndex[j] = len(ps.columns)
+			ps.columns = append(ps.columns, j)
+		} else {
+			objConstant += lp.ObjCoeff[j] * ps.fixed[j]
+		}
+	}
+	for i, active := range p.rowActive {
+		if active {
+			ps.rows = append(ps.rows, i)
+		}
+	}
+
+	reduced := &model.LinearProgram{
+		NbConstraints:   len(ps.rows),
+		NbVariables:     len(ps.columns),
+		Objective:       lp.Objective,
+		ConstraintCoeff: model.NewSparseMatrix(len(ps.rows), len(ps.columns)),
+		ObjConstant:     objConstant,
+	}
+	for _, j := range ps.columns {
+		reduced.VariableNames = append(reduced.VariableNames, lp.VariableName(j))
+		reduced.ObjCoeff = append(reduced.ObjCoeff, lp.ObjCoeff[j])
+		reduced.LowerBounds = append(reduced.LowerBounds, p.lower[j])
+		reduced.UpperBounds = append(reduced.UpperBounds, p.upper[j])
+		if lp.Types != nil {
+			reduced.Types = append(reduced.Types, lp.Types[j])
+		}
+	}
+	for r, i := range ps.rows {
+		cols, coeffs := p.rowEntries(i)
+		for k, j := range cols {
+			reduced.ConstraintCoeff.Rows[r].Indices = append(reduced.ConstraintCoeff.Rows[r].Indices, newIndex[j])
+			reduced.ConstraintCoeff.Rows[r].Values = append(reduced.ConstraintCoeff.Rows[r].Values, coeffs[k])
+		}
+		reduced.Comparisons = append(reduced.Comparisons, lp.Comparisons[i])
+		reduced.Rhs = append(reduced.Rhs, p.rhs[i])
+		if lp.ConstraintTexts != nil {
+			reduced.ConstraintTexts = append(reduced.ConstraintTexts, lp.ConstraintTexts[i])
+		}
+	}
+	return reduced
+}
+
+// Apply sets the solution of the original problem from the Result of the reduced problem: ObjVar, and the Duals, Slacks
+// and ReducedCosts when the reduced solve computed duals. The removed variables take their fixed values and the
+// removed constraints a zero dual value. Then, going back through the tightened bounds, a variable that ends on a
+// bound implied by a constraint, with a reduced cost that pushes against that bound, hands its reduced cost over to
+// the dual value of that constraint, since the bound does not exist in the original problem. The sensitivity ranges
+// and the basis are not mapped back.
+func (ps *Postsolve) Apply(lp *model.LinearProgram, reduced *Result) {
+	original := ps.original
+	values := make([]float64, original.NbVariables)
+	for k, j := range ps.columns {
+		values[j] = reduced.ObjVar[k]
+	}
+	for j, value := range ps.fixed {
+		values[j] = value
+	}
+	lp.ObjVar = append(values, reduced.ObjVar[len(reduced.ObjVar)-1])
+	lp.Sensitivity, lp.Basis = nil, nil
+	lp.AlternativeOptima = reduced.AlternativeOptima
+	lp.Duals, lp.Slacks, lp.ReducedCosts = nil, nil, nil
+	if reduced.Duals == nil {
+		return
+	}
+
+	duals := make([]float64, original.NbConstraints)
+	for r, i := range ps.rows {
+		duals[i] = reduced.Duals[r]
+	}
+	sense := 1.0
+	if original.Objective == model.MINIMIZE {
+		sense = -1
+	}
+	columns := original.ConstraintCoeff.Transpose()
+	reducedCost := func(j int) float64 {
+		return original.ObjCoeff[j] - columns.Rows[j].Dot(duals)
+	}
+	for k := len(ps.stack) - 1; k >= 0; k-- {
+		bound := ps.stack[k]
+		if math.Abs(values[bound.col]-bound.value) > presolveTolerance(bound.value) {
+			continue
+		}
+		// A positive reduced cost of the maximized objective pushes the variable up, a negative one down
+		d := reducedCost(bound.col)
+		if bound.upper && sense*d > 1e-12 || !bound.upper && sense*d < -1e-12 {
+			duals[bound.row] += d / bound.coeff
+		}
+	}
+
+	lp.Duals = duals
+	lp.Slacks = make([]float64, original.NbConstraints)
+	for i, row := range original.ConstraintCoeff.Rows {
+		lp.Slacks[i] = original.Rhs[i] - row.Dot(values)
+		if !hasUpperSide(original.Comparisons[i]) {
+			lp.Slacks[i] *= -1
+		}
+	}
+	lp.ReducedCosts = make([]float64, original.NbVariables)
+	for j := range lp.ReducedCosts {
+		lp.ReducedCosts[j] = reducedCost(j)
+	}
+}
+
+// solvePresolved solves the problem through Presolve and maps the solution back. The reductions do not carry the
+// certificates over, so an infeasible or unbounded problem is solved again without presolve to get one. A solve
+// stopped by a limit is not, and its basis, which belongs to the reduced problem, is dropped.
+func solvePresolved(lp *model.LinearProgram, opts SolveOptions, lim *limits) error {
+	opts.Presolve = false
+	reduced, postsolve, err := Presolve(lp)
+	if err == nil {
+		reducedOpts := opts
+		reducedOpts.WarmStart = nil // The basis of the original problem does not fit the reduced one
+		err = solve(reduced, reducedOpts, lim)
+	}
+	var limitErr *LimitError
+	if errors.As(err, &limitErr) {
+		limitErr.Basis = nil
+		return err
+	}
+	if err != nil {
+		if opts.trace != nil {
+			*opts.trace = Trace{} // The trace of the reduced problem gives way to the one of the original problem
+		}
+		return solve(lp, opts, lim)
+	}
+	postsolve.Apply(lp, solvedResult(reduced))
+	return nil
+}
@@ -0,0 +1,191 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestPresolve(t *testing.T) {
+	tests := []struct {
+		name           string
+		build          func() *model.LinearProgram
+		reducedRows    int
+		reducedColumns int
+		expectedObjVar []float64
+	}{
+		{
+			// maximize 3x + 5y + 2z - w with z fixed at 1: the singletons x <= 4 and 2y <= 12 become bounds, the
+			// second row is twice the first one with a looser RHS, the empty row holds, and w only hurts
+			name: "Redundant",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints: 6,
+					NbVariables:   4,
+					VariableNames: []string{"x", "y", "z", "w"},
+					Objective:     model.MAXIMIZE,
+					ObjCoeff:      []float64{3, 5, 2, -1},
+					Comparisons:   []model.Comparison{model.LE, model.LE, model.LE, model.LE, model.LE, model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+						{3, 2, 1, 0},
+						{6, 4, 2, 0},
+						{1, 0, 0, 0},
+						{0, 2, 0, 0},
+						{0, 0, 0, 0},
+						{1, 0, 0, -1},
+					}),
+					Rhs:         []float64{18, 40, 4, 12, 5, 10},
+					LowerBounds: []float64{0, 0, 1, 0},
+					UpperBounds: []float64{math.Inf(1), math.Inf(1), 1, math.Inf(1)},
+				}
+			},
+			reducedRows:    1,
+			reducedColumns: 2,
+			expectedObjVar: []float64{5.0 / 3, 6, 1, 0, 37},
+		},
+		{
+			// minimize 2x + 3y + 4z subject to x + y + z = 10, x + y >= 4 and its negated duplicate, x <= 3
+			name: "EqualityMinimize",
+			build: func() *model.LinearProgram {
+				return &model.LinearProgram{
+					NbConstraints: 3,
+					NbVariables:   3,
+					VariableNames: []string{"x", "y", "z"},
+					Objective:     model.MINIMIZE,
+					ObjCoeff:      []float64{2, 3, 4},
+					Comparisons:   []model.Comparison{model.EQ, model.BE, model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+						{1, 1, 1},
+						{1, 1, 0},
+						{-1, -1, 0},
+					}),
+					Rhs:         []float64{10, 4, -4},
+					UpperBounds: []float64{3, math.Inf(1), math.Inf(1)},
+				}
+			},
+			reducedRows:    2,
+			reducedColumns: 3,
+			expectedObjVar: []float64{3, 7, 0, 27},
+		},
+	}
+
+	algorithms := map[string]SolveOptions{
+		"PrimalSimplex":  {Algorithm: PrimalSimplex},
+		"BigM":           {Algorithm: PrimalSimplex, Initialization: BigM},
+		"RevisedSimplex": {Algorithm: RevisedSimplex},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lp := tt.build()
+			reduced, postsolve, err := Presolve(lp)
+			if err != nil {
+				t.Fatalf("Presolve() error = %v", err)
+			}
+			if reduced.NbConstraints != tt.reducedRows || reduced.NbVariables != tt.reducedColumns {
+				t.Errorf("Expected %d rows and %d columns, got %d and %d (%+v)",
+					tt.reducedRows, tt.reducedColumns, reduced.NbConstraints, reduced.NbVariables, postsolve.Stats)
+			}
+			if !reflect.DeepEqual(lp, tt.build()) {
+				t.Errorf("Expected Presolve() to leave the problem unchanged")
+			}
+		})
+
+		for name, opts := range algorithms {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				direct := tt.build()
+				directResult, err := SolveWithOptions(context.Background(), direct, opts)
+				if err != nil {
+					t.Fatalf("SolveWithOptions() error = %v", err)
+				}
+
+				lp := tt.build()
+				opts.Presolve = true
+				result, err := SolveWithOptions(context.Background(), lp, opts)
+				if err != nil {
+					t.Fatalf("SolveWithOptions() with presolve error = %v", err)
+				}
+				if !equalFloat64Slices(result.ObjVar, tt.expectedObjVar, 1e-9) {
+					t.Errorf("Expected %v, got %v", tt.expectedObjVar, result.ObjVar)
+				}
+				// Both problems have a single optimal dual solution, which postsolve must recover
+				if !equalFloat64Slices(result.Duals, directResult.Duals, 1e-9) {
+					t.Errorf("Expected the duals %v, got %v", directResult.Duals, result.Duals)
+				}
+				if !equalFloat64Slices(result.ReducedCosts, directResult.ReducedCosts, 1e-9) {
+					t.Errorf("Expected the reduced costs %v, got %v", directResult.ReducedCosts, result.ReducedCosts)
+				}
+				if !equalFloat64Slices(result.Slacks, directResult.Slacks, 1e-9) {
+					t.Errorf("Expected the slacks %v, got %v", directResult.Slacks, result.Slacks)
+				}
+			})
+		}
+	}
+}
+
+func TestPresolve_Infeasible(t *testing.T) {
+	// x + y <= 2 and 2x + 2y >= 10 are duplicates that contradict each other
+	lp := &model.LinearProgram{
+		NbConstraints: 2,
+		NbVariables:   2,
+		VariableNames: []string{"x", "y"},
+		Objective:     model.MAXIMIZE,
+		ObjCoeff:      []float64{1, 1},
+		Comparisons:   []model.Comparison{model.LE, model.BE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 1},
+			{2, 2},
+		}),
+		Rhs: []float64{2, 10},
+	}
+	var infeasible *InfeasibleError
+	if _, _, err := Presolve(lp); !errors.As(err, &infeasible) {
+		t.Errorf("Expected Presolve() to return an InfeasibleError, got %v", err)
+	}
+
+	// Solving with presolve falls back to the original problem for the certificate
+	_, err := SolveWithOptions(context.Background(), lp.Clone(), SolveOptions{Presolve: true})
+	if !errors.As(err, &infeasible) {
+		t.Fatalf("Expected an InfeasibleError, got %v", err)
+	}
+	if err := CheckCertificate(lp, err); err != nil {
+		t.Errorf("CheckCertificate() error = %v", err)
+	}
+}
+
+func TestPresolve_AllReduced(t *testing.T) {
+	// maximize x + y subject to x <= 2, y <= 3: both singletons become bounds, where the dominated columns are fixed
+	lp := &model.LinearProgram{
+		NbConstraints: 2,
+		NbVariables:   2,
+		VariableNames: []string{"x", "y"},
+		Objective:     model.MAXIMIZE,
+		ObjCoeff:      []float64{1, 1},
+		Comparisons:   []model.Comparison{model.LE, model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 0},
+			{0, 1},
+		}),
+		Rhs: []float64{2, 3},
+	}
+	reduced, postsolve, err := Presolve(lp)
+	if err != nil {
+		t.Fatalf("Presolve() error = %v", err)
+	}
+	expected := PresolveStats{RemovedRows: 2, RemovedColumns: 2, TightenedBounds: 2}
+	if reduced.NbConstraints != 0 || reduced.NbVariables != 0 || postsolve.Stats != expected {
+		t.Errorf("Expected an empty problem with %+v, got %+v", expected, postsolve.Stats)
+	}
+
+	result, err := SolveWithOptions(context.Background(), lp, SolveOptions{Presolve: true})
+	if err != nil {
+		t.Fatalf("SolveWithOptions() error = %v", err)
+	}
+	if !equalFloat64Slices(result.ObjVar, []float64{2, 3, 5}, 1e-9) || !equalFloat64Slices(result.Duals, []float64{1, 1}, 1e-9) {
+		t.Errorf("Expected [2 3 5] with the duals [1 1], got %v and %v", result.ObjVar, result.Duals)
+	}
+}
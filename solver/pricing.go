@@ -0,0 +1,392 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Pricing selects the rule used by the primal simplex to choose the entering variable.
+type Pricing int
+
+const (
+	BlandPricing        Pricing = iota // First improving column, never cycles
+	DantzigPricing                     // Most negative reduced cost
+	DevexPricing                       // Reduced cost scaled by approximate edge norms
+	SteepestEdgePricing                // Reduced cost scaled by exact edge norms
+	PartialPricing                     // Dantzig on one segment of the columns at a time, keeping a short candidate list
+)
+
+// DegeneratePivotLimit is the number of consecutive degenerate pivots after which the primal simplex
+// falls back to Bland's rule until the objective improves again, so that no pricing rule can cycle.
+const DegeneratePivotLimit = 50
+
+// PartialPricingSegments is the number of segments the columns are split into by PartialPricing.
+const PartialPricingSegments = 4
+
+// MultiplePricingCandidates is the number of improving columns PartialPricing keeps from a segment.
+const MultiplePricingCandidates = 5
+
+// PricingRule chooses the entering variable of the primal simplex on a SimplexTable.
+type PricingRule interface {
+	// Reset prepares the rule for a new tableau.
+	Reset(table *SimplexTable)
+	// SelectEntering returns the entering column, or -1 if no column improves the objective.
+	SelectEntering(table *SimplexTable) int
+	// Pivoting is called just before the tableau pivots on (pivotRow, pivotCol).
+	Pivoting(table *SimplexTable, pivotRow, pivotCol int)
+}
+
+// NewPricingRule returns a fresh rule for the given pricing.
+func NewPricingRule(pricing Pricing) PricingRule {
+	switch pricing {
+	case DantzigPricing:
+		return &dantzigRule{}
+	case DevexPricing:
+		return &devexRule{}
+	case SteepestEdgePricing:
+		return &steepestEdgeRule{}
+	case PartialPricing:
+		return &partialRule{}
+	default:
+		return &blandRule{}
+	}
+}
+
+// String returns the name of the pricing rule.
+func (pricing Pricing) String() string {
+	switch pricing {
+	case BlandPricing:
+		return "Bland"
+	case DantzigPricing:
+		return "Dantzig"
+	case DevexPricing:
+		return "Devex"
+	case SteepestEdgePricing:
+		return "SteepestEdge"
+	case PartialPricing:
+		return "Partial"
+	}
+	return fmt.Sprintf("Pricing(%d)", int(pricing))
+}
+
+// pricingOf returns the pricing of a rule returned by NewPricingRule, BlandPricing for nil.
+func pricingOf(rule PricingRule) Pricing {
+	switch rule.(type) {
+	case *dantzigRule:
+		return DantzigPricing
+	case *devexRule:
+		return DevexPricing
+	case *steepestEdgeRule:
+		return SteepestEdgePricing
+	case *partialRule:
+		return PartialPricing
+	default:
+		return BlandPricing
+	}
+}
+
+// reducedCost returns the objective row entry of the column and whether it improves the objective.
+func (table *SimplexTable) reducedCost(col int) (float64, bool) {
+	epsilon := 1e-10
+	if table.isArtificial(col) {
+		return 0, false // Artificial variables never re-enter the basis
+	}
+	coefficient := table.data[len(table.data)-1][col]
+	return coefficient, coefficient < -epsilon
+}
+
+// numVariableColumns returns the number of columns before the RHS column.
+func (table *SimplexTable) numVariableColumns() int {
+	return len(table.data[0]) - 1
+}
+
+// selectBest returns the improving column with the largest score d_j^2 / weight(j), the first one on ties.
+func (table *SimplexTable) selectBest(weight func(col int) float64) int {
+	best, bestScore := -1, 0.0
+	for j := 0; j < table.numVariableColumns(); j++ {
+		d, ok := table.reducedCost(j)
+		if !ok {
+			continue
+		}
+		if score := d * d / weight(j); best == -1 || score > bestScore {
+			best, bestScore = j, score
+		}
+	}
+	return best
+}
+
+// blandRule selects the first improving column.
+type blandRule struct{}
+
+func (*blandRule) Reset(*SimplexTable)              {}
+func (*blandRule) Pivoting(*SimplexTable, int, int) {}
+
+func (*blandRule) SelectEntering(table *SimplexTable) int {
+	return table.FindEnteringVariable()
+}
+
+// dantzigRule selects the column with the most negative reduced cost.
+type dantzigRule struct{}
+
+func (*dantzigRule) Reset(*SimplexTable)              {}
+func (*dantzigRule) Pivoting(*SimplexTable, int, int) {}
+
+func (*dantzigRule) SelectEntering(table *SimplexTable) int {
+	return table.selectBest(func(int) float64 { return 1 })
+}
+
+// steepestEdgeRule selects the column whose edge improves the objective the most per unit of length,
+// with the exact norms 1 + sum of the squared column entries, which the tableau holds explicitly.
+type steepestEdgeRule struct{}
+
+func (*steepestEdgeRule) Reset(*SimplexTable)              {}
+func (*steepestEdgeRule) Pivoting(*SimplexTable, int, int) {}
+
+func (*steepestEdgeRule) SelectEntering(table *SimplexTable) int {
+	numConstraintRows := len(table.data) - 1
+	return table.selectBest(func(col int) float64 {
+		norm := 1.0
+		for i := 0; i < numConstraintRows; i++ {
+			norm += table.data[i][col] * table.data[i][col]
+		}
+		return norm
+	})
+}
+
+// devexRule approximates the steepest edge norms with Forrest and Goldfarb's Devex reference weights,
+// which are updated from the pivot row instead of being recomputed.
+type devexRule struct {
+	weights []float64
+}
+
+func (rule *devexRule) Reset(table *SimplexTable) {
+	rule.weights = nil
+	rule.grow(table)
+}
+
+// grow gives a weight of 1 to the columns added since the last call, e.g. artificial variables.
+func (rule *devexRule) grow(table *SimplexTable) {
+	for len(rule.weights) < table.numVariableColumns() {
+		rule.weights = append(rule.weights, 1)
+	}
+}
+
+func (rule *devexRule) SelectEntering(table *SimplexTable) int {
+	rule.grow(table)
+	return table.selectBest(func(col int) float64 { return rule.weights[col] })
+}
+
+func (rule *devexRule) Pivoting(table *SimplexTable, pivotRow, pivotCol int) {
+	rule.grow(table)
+	pivotElement := table.data[pivotRow][pivotCol]
+	enteringWeight := rule.weights[pivotCol]
+
+	for j := 0; j < table.numVariableColumns(); j++ {
+		ratio := table.data[pivotRow][j] / pivotElement
+		rule.weights[j] = math.Max(rule.weights[j], ratio*ratio*enteringWeight)
+	}
+	leaving := int(table.basicVariables[pivotRow])
+	rule.weights[leaving] = math.Max(enteringWeight/(pivotElement*pivotElement), 1)
+}
+
+// partialRule prices one segment of the columns at a time and keeps the best improving columns
+// of that segment as candidates (multiple pricing), which are tried first in the next iterations.
+type partialRule struct {
+	start      int
+	candidates []int
+}
+
+func (rule *partialRule) Reset(*SimplexTable) {
+	rule.start = 0
+	rule.candidates = nil
+}
+
+func (*partialRule) Pivoting(*SimplexTable, int, int) {}
+
+func (rule *partialRule) SelectEntering(table *SimplexTable) int {
+	// Reuse the candidates that still improve the objective
+	if col := rule.bestCandidate(table); col != -1 {
+		return col
+	}
+
+	numCols := table.numVariableColumns()
+	segmentSize := (numCols + PartialPricingSegments - 1) / PartialPricingSegments
+	for scanned := 0; scanned < numCols; scanned += segmentSize {
+		for offset := 0; offset < segmentSize && scanned+offset < numCols; offset++ {
+			col := (rule.start + scanned + offset) % numCols
+			if _, ok := table.reducedCost(col); ok {
+				rule.candidates = append(rule.candidates, col)
+			}
+		}
+		if len(rule.candidates) > 0 {
+			rule.start = (rule.start + scanned + segmentSize) % numCols
+			sort.SliceStable(rule.candidates, func(a, b int) bool {
+				return table.data[len(table.data)-1][rule.candidates[a]] < table.data[len(table.data)-1][rule.candidates[b]]
+			})
+			if len(rule.candidates) > MultiplePricingCandidates {
+				rule.candidates = rule.candidates[:MultiplePricingCandidates]
+			}
+			return rule.bestCandidate(table)
+		}
+	}
+
+	return -1
+}
+
+// bestCandidate removes the candidates that no longer improve the objective
+// and returns the remaining one with the most negative reduced cost, or -1.
+func (rule *partialRule) bestCandidate(table *SimplexTable) int {
+	best, bestCost := -1, 0.0
+	remaining := rule.candidates[:0]
+	for _, col := range rule.candidates {
+		d, ok := table.reducedCost(col)
+		if !ok {
+			continue
+		}
+		remaining = append(remaining, col)
+		if best == -1 || d < bestCost {
+			best, bestCost = col, d
+		}
+	}
+	rule.candidates = remaining
+	return best
+}
+
+// selectEntering chooses the entering column of the revised simplex for the given costs with its pricing rule, or
+// with Bland's rule when bland is set. The reduced costs are priced against the duals computed from the LU.
+func (rs *RevisedSimplexSolver) selectEntering(cost []float64, bland bool) int {
+	if bland || rs.pricing == BlandPricing {
+		return rs.FindEnteringVariable(cost)
+	}
+	y := rs.duals(cost)
+	if rs.pricing == PartialPricing {
+		return rs.partialEntering(cost, y)
+	}
+
+	// Dantzig without weights, Devex and steepest edge relative to the weight of each column
+	best, bestScore := -1, 0.0
+	for j := 0; j < rs.numCols; j++ {
+		d := rs.improvement(cost, y, j)
+		if d == 0 {
+			continue
+		}
+		score := d * d
+		if rs.weights != nil {
+			score /= rs.weights[j]
+		}
+		if score > bestScore {
+			best, bestScore = j, score
+		}
+	}
+	return best
+}
+
+// resetPricing prepares the pricing rule for a new solve from the current basis. Devex starts from reference
+// weights of 1, and steepest edge from the exact norms 1 + |B^-1 a_j|^2 of the columns.
+func (rs *RevisedSimplexSolver) resetPricing() {
+	rs.weights, rs.partialStart, rs.candidates = nil, 0, nil
+	switch rs.pricing {
+	case DevexPricing:
+		rs.weights = make([]float64, rs.numCols)
+		for j := range rs.weights {
+			rs.weights[j] = 1
+		}
+	case SteepestEdgePricing:
+		rs.weights = make([]float64, rs.numCols)
+		for j := range rs.weights {
+			rs.weights[j] = 1
+			if rs.basicRow[j] == -1 {
+				alpha := rs.Ftran(rs.column(j))
+				rs.weights[j] += dot(alpha, alpha)
+			}
+		}
+	}
+}
+
+// updateWeights updates the Devex or steepest edge weights before the pivot on (pivotRow, pivotCol), where alpha
+// is the entering column B^-1 a_q. Both need the pivot row of B^-1 A, which is priced against row pivotRow of B^-1.
+// Steepest edge keeps the norms exact with Goldfarb and Reid's update, which also needs B^-T alpha.
+func (rs *RevisedSimplexSolver) updateWeights(pivotRow, pivotCol int, alpha []float64) {
+	unit := make([]float64, rs.numRows)
+	unit[pivotRow] = 1
+	rowOfInverse := rs.Btran(unit)
+	var tau []float64
+	if rs.pricing == SteepestEdgePricing {
+		tau = rs.Btran(alpha)
+	}
+
+	pivotElement := alpha[pivotRow]
+	enteringWeight := 1.0
+	if pivotCol < rs.numCols {
+		enteringWeight = rs.weights[pivotCol]
+	}
+	for j := 0; j < rs.numCols; j++ {
+		if rs.basicRow[j] != -1 || j == pivotCol {
+			continue
+		}
+		ratio := rs.columns[j].Dot(rowOfInverse) / pivotElement
+		if ratio == 0 {
+			continue
+		}
+		if tau == nil {
+			rs.weights[j] = math.Max(rs.weights[j], ratio*ratio*enteringWeight)
+			continue
+		}
+		weight := rs.weights[j] - 2*ratio*rs.columns[j].Dot(tau) + ratio*ratio*enteringWeight
+		rs.weights[j] = math.Max(weight, 1+ratio*ratio)
+	}
+	if leaving := rs.basis[pivotRow]; leaving < rs.numCols {
+		rs.weights[leaving] = math.Max(enteringWeight/(pivotElement*pivotElement), 1)
+	}
+}
+
+// partialEntering prices one segment of the columns at a time like partialRule, keeping the best improving
+// columns of that segment as candidates, which are tried first in the next iterations.
+func (rs *RevisedSimplexSolver) partialEntering(cost, y []float64) int {
+	if col := rs.bestCandidate(cost, y); col != -1 {
+		return col
+	}
+
+	segmentSize := (rs.numCols + PartialPricingSegments - 1) / PartialPricingSegments
+	for scanned := 0; scanned < rs.numCols; scanned += segmentSize {
+		improvements := make(map[int]float64)
+		for offset := 0; offset < segmentSize && scanned+offset < rs.numCols; offset++ {
+			col := (rs.partialStart + scanned + offset) % rs.numCols
+			if d := rs.improvement(cost, y, col); d > 0 {
+				rs.candidates = append(rs.candidates, col)
+				improvements[col] = d
+			}
+		}
+		if len(rs.candidates) > 0 {
+			rs.partialStart = (rs.partialStart + scanned + segmentSize) % rs.numCols
+			sort.SliceStable(rs.candidates, func(a, b int) bool {
+				return improvements[rs.candidates[a]] > improvements[rs.candidates[b]]
+			})
+			if len(rs.candidates) > MultiplePricingCandidates {
+				rs.candidates = rs.candidates[:MultiplePricingCandidates]
+			}
+			return rs.bestCandidate(cost, y)
+		}
+	}
+	return -1
+}
+
+// bestCandidate removes the candidates that no longer improve the objective and returns the remaining one that
+// improves it the fastest, or -1.
+func (rs *RevisedSimplexSolver) bestCandidate(cost, y []float64) int {
+	best, bestImprovement := -1, 0.0
+	remaining := rs.candidates[:0]
+	for _, col := range rs.candidates {
+		d := rs.improvement(cost, y, col)
+		if d == 0 {
+			continue
+		}
+		remaining = append(remaining, col)
+		if d > bestImprovement {
+			best, bestImprovement = col, d
+		}
+	}
+	rs.candidates = remaining
+	return best
+}
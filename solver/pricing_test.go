@@ -0,0 +1,159 @@
+package solver
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// pricingAlgorithms are the simplex implementations that apply the pricing rules.
+var pricingAlgorithms = map[string]Algorithm{
+	"Tableau": PrimalSimplex,
+	"Revised": RevisedSimplex,
+}
+
+var pricingRules = map[string]Pricing{
+	"Bland":        BlandPricing,
+	"Dantzig":      DantzigPricing,
+	"Devex":        DevexPricing,
+	"SteepestEdge": SteepestEdgePricing,
+	"Partial":      PartialPricing,
+}
+
+// randomPricingProblem builds a feasible problem with >= rows, so that Phase I is needed, and some bounded variables.
+func randomPricingProblem(seed int64, m, n int) *model.LinearProgram {
+	rng := rand.New(rand.NewSource(seed))
+	lp := &model.LinearProgram{NbConstraints: m, NbVariables: n, Objective: model.MAXIMIZE}
+	for j := 0; j < n; j++ {
+		lp.ObjCoeff = append(lp.ObjCoeff, 1+rng.Float64()*9)
+		if j%4 == 0 {
+			lp.UpperBounds = append(lp.UpperBounds, 1)
+		} else {
+			lp.UpperBounds = append(lp.UpperBounds, math.Inf(1))
+		}
+	}
+	lp.ConstraintCoeff = model.NewSparseMatrix(m, n)
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			if rng.Float64() < 0.5 {
+				lp.ConstraintCoeff.Set(i, j, rng.Float64()*10)
+			}
+		}
+		lp.ConstraintCoeff.Set(i, i%n, 1+rng.Float64())
+		if i%4 == 0 {
+			lp.Comparisons = append(lp.Comparisons, model.BE)
+			lp.Rhs = append(lp.Rhs, 1+rng.Float64())
+		} else {
+			lp.Comparisons = append(lp.Comparisons, model.LE)
+			lp.Rhs = append(lp.Rhs, 50+rng.Float64()*50)
+		}
+	}
+	return lp
+}
+
+func TestPricingRules_SameOptimum(t *testing.T) {
+	reference := randomPricingProblem(3, 30, 45)
+	referenceResult, err := Solve(reference)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	objective := len(referenceResult.ObjVar) - 1
+
+	for algorithmName, algorithm := range pricingAlgorithms {
+		for name, pricing := range pricingRules {
+			t.Run(algorithmName+"/"+name, func(t *testing.T) {
+				lp := randomPricingProblem(3, 30, 45)
+				result, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: algorithm, Pricing: pricing})
+				if err != nil {
+					t.Fatalf("SolveWithOptions() error = %v", err)
+				}
+				if math.Abs(result.ObjVar[objective]-referenceResult.ObjVar[objective]) > 1e-6 {
+					t.Errorf("Expected objective %v, got %v", referenceResult.ObjVar[objective], result.ObjVar[objective])
+				}
+			})
+		}
+	}
+}
+
+func TestPricingRules_Unbounded(t *testing.T) {
+	for algorithmName, algorithm := range pricingAlgorithms {
+		for name, pricing := range pricingRules {
+			t.Run(algorithmName+"/"+name, func(t *testing.T) {
+				lp := &model.LinearProgram{
+					NbConstraints:   1,
+					NbVariables:     2,
+					Objective:       model.MAXIMIZE,
+					ObjCoeff:        []float64{1, 1},
+					Comparisons:     []model.Comparison{model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, -1}}),
+					Rhs:             []float64{1},
+				}
+				_, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: algorithm, Pricing: pricing})
+				if err == nil || err.Error() != "unbounded problem" {
+					t.Errorf("Expected error to be 'unbounded problem', got %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestPricingRules_Cycling(t *testing.T) {
+	// Chvatal's example cycles with the largest coefficient rule, the Bland fallback has to break the cycle
+	for algorithmName, algorithm := range pricingAlgorithms {
+		for name, pricing := range pricingRules {
+			t.Run(algorithmName+"/"+name, func(t *testing.T) {
+				lp := &model.LinearProgram{
+					NbConstraints: 3,
+					NbVariables:   4,
+					Objective:     model.MAXIMIZE,
+					ObjCoeff:      []float64{10, -57, -9, -24},
+					Comparisons:   []model.Comparison{model.LE, model.LE, model.LE},
+					ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+						{0.5, -5.5, -2.5, 9},
+						{0.5, -1.5, -0.5, 1},
+						{1, 0, 0, 0},
+					}),
+					Rhs: []float64{0, 0, 1},
+				}
+				result, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: algorithm, Pricing: pricing})
+				if err != nil {
+					t.Fatalf("SolveWithOptions() error = %v", err)
+				}
+				if math.Abs(result.ObjVar[4]-1) > 1e-9 {
+					t.Errorf("Expected objective 1, got %v", result.ObjVar[4])
+				}
+			})
+		}
+	}
+}
+
+func TestPricingRules_RevisedSimplexIterations(t *testing.T) {
+	iterations := make(map[Pricing]int)
+	for _, pricing := range []Pricing{BlandPricing, DantzigPricing, DevexPricing} {
+		lp := randomPricingProblem(5, 60, 120)
+		result, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: RevisedSimplex, Pricing: pricing})
+		if err != nil {
+			t.Fatalf("SolveWithOptions() error = %v", err)
+		}
+		iterations[pricing] = result.Iterations
+	}
+	if iterations[DantzigPricing] >= iterations[BlandPricing] || iterations[DevexPricing] >= iterations[BlandPricing] {
+		t.Errorf("Expected Dantzig and Devex to need fewer iterations than Bland, got %v", iterations)
+	}
+}
+
+func BenchmarkPricingRules(b *testing.B) {
+	for name, pricing := range pricingRules {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				lp := randomPricingProblem(int64(i), 60, 90)
+				if _, err := SolveWithOptions(context.Background(), lp, SolveOptions{Pricing: pricing}); err != nil {
+					b.Fatalf("SolveWithOptions() error = %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// Status tells how a solve ended.
+type Status int
+
+const (
+	Optimal        Status = iota + 1 // An optimal solution was found
+	Infeasible                       // No point satisfies the constraints
+	Unbounded                        // The objective improves without limit
+	IterationLimit                   // SolveOptions.MaxIterations iterations were done
+	TimeLimit                        // SolveOptions.TimeLimit or the deadline of the context passed
+	Canceled                         // The context was canceled
+)
+
+func (s Status) String() string {
+	switch s {
+	case Optimal:
+		return "Optimal"
+	case Infeasible:
+		return "Infeasible"
+	case Unbounded:
+		return "Unbounded"
+	case IterationLimit:
+		return "IterationLimit"
+	case TimeLimit:
+		return "TimeLimit"
+	case Canceled:
+		return "Canceled"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// The sentinel errors of the statuses other than Optimal. The errors returned by the solver match them with
+// errors.Is, and errors.As gives the *InfeasibleError, *UnboundedError or *LimitError with their details.
+var (
+	ErrInfeasible     = errors.New("infeasible problem")
+	ErrUnbounded      = errors.New("unbounded problem")
+	ErrIterationLimit = errors.New("iteration limit reached")
+	ErrTimeLimit      = errors.New("time limit reached")
+	ErrCanceled       = errors.New("solve canceled")
+)
+
+// statusOf returns the status of a solve that ended with the error, and false for an error that has none, such as
+// invalid bounds.
+func statusOf(err error) (Status, bool) {
+	var limitErr *LimitError
+	switch {
+	case err == nil:
+		return Optimal, true
+	case errors.Is(err, ErrInfeasible):
+		return Infeasible, true
+	case errors.Is(err, ErrUnbounded):
+		return Unbounded, true
+	case errors.As(err, &limitErr):
+		return limitErr.Status, true
+	}
+	return 0, false
+}
+
+// Result holds the outcome of Solve, SolveWithOptions and SolveMIP. The solution fields, from Objective to
+// AlternativeOptima, are only set when Status is Optimal; they have the meaning of the fields of the same name of
+// model.LinearProgram. Basis is also set for a solve stopped by a limit, to continue it as a warm start. SolveMIP
+// only sets Objective, Values and ObjVar, also for a search stopped by MaxNodes after an integer solution was found.
+type Result struct {
+	Status            Status
+	Objective         float64
+	Values            map[string]float64 // Value of each variable, keyed by its name, see LinearProgram.VariableName
+	ObjVar            []float64          // Value of each variable in order, followed by the objective value
+	Duals             []float64
+	Slacks            []float64
+	ReducedCosts      []float64
+	Sensitivity       *model.Sensitivity
+	Basis             *model.Basis
+	AlternativeOptima bool
+	Iterations        int           // Simplex pivots or interior-point iterations, over all the phases
+	Duration          time.Duration // Wall-clock time of the solve
+	Trace             *Trace        // Iterations of the tableau simplex, with SolveOptions.Trace
+	MIP               *MIPStats     // Final statistics of the branch and bound, from SolveMIP
+
+	solution *model.LinearProgram // Copy of the problem holding the solution, for GetSolutionJSON
+}
+
+// optimalResult returns the Result of solved, the copy of lp solved in place.
+func optimalResult(lp, solved *model.LinearProgram) *Result {
+	r := solvedResult(solved)
+	r.Objective = solved.ObjVar[lp.NbVariables]
+	r.Values = make(map[string]float64, lp.NbVariables)
+	for j, value := range solved.ObjVar[:lp.NbVariables] {
+		r.Values[lp.VariableName(j)] = value
+	}
+	r.solution = lp.Clone()
+	copySolution(r.solution, solved)
+	return r
+}
+
+// solvedResult returns the solution fields of a problem solved in place as an Optimal Result.
+func solvedResult(solved *model.LinearProgram) *Result {
+	return &Result{
+		Status:            Optimal,
+		ObjVar:            solved.ObjVar,
+		Duals:             solved.Duals,
+		Slacks:            solved.Slacks,
+		ReducedCosts:      solved.ReducedCosts,
+		Sensitivity:       solved.Sensitivity,
+		Basis:             solved.Basis,
+		AlternativeOptima: solved.AlternativeOptima,
+	}
+}
+
+// copySolution sets the solution fields of lp to the ones of the solved problem.
+func copySolution(lp, solved *model.LinearProgram) {
+	lp.ObjVar = solved.ObjVar
+	lp.Duals, lp.Slacks, lp.ReducedCosts = solved.Duals, solved.Slacks, solved.ReducedCosts
+	lp.Sensitivity = solved.Sensitivity
+	lp.Basis = solved.Basis
+	lp.AlternativeOptima = solved.AlternativeOptima
+}
+
+// GetSolutionJSON returns the solution in JSON format, like LinearProgram.GetSolutionJSON. It fails unless Status
+// is Optimal.
+func (r *Result) GetSolutionJSON() (string, error) {
+	if r.Status != Optimal {
+		return "", fmt.Errorf("solution not available: %v", r.Status)
+	}
+	return r.solution.GetSolutionJSON()
+}
@@ -0,0 +1,95 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestSolve_LeavesProblemUnchanged(t *testing.T) {
+	lp := parametricProblem()
+	lp.Signs = []model.Sign{model.Free, model.NonNegative}
+	original := lp.Clone()
+
+	first, err := Solve(lp)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if !reflect.DeepEqual(lp, original) {
+		t.Fatalf("Expected the problem to be left unchanged, got %+v", lp)
+	}
+
+	// Solving the same problem again gives the same result
+	second, err := Solve(lp)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if !equalFloat64Slices(second.ObjVar, first.ObjVar, 1e-9) {
+		t.Errorf("Expected %v again, got %v", first.ObjVar, second.ObjVar)
+	}
+
+	if first.Status != Optimal || first.Objective != 36 || first.Iterations == 0 || first.Duration <= 0 {
+		t.Errorf("Expected an optimum of 36 with its iterations and duration, got %+v", first)
+	}
+	if !reflect.DeepEqual(first.Values, map[string]float64{"x": 2, "y": 6}) {
+		t.Errorf("Expected the values map[x:2 y:6], got %v", first.Values)
+	}
+}
+
+func TestSolveWithOptions_Status(t *testing.T) {
+	infeasible := parametricProblem()
+	infeasible.Comparisons[2] = model.BE
+	infeasible.Rhs[2] = 40
+
+	unbounded := parametricProblem()
+	unbounded.Comparisons[1], unbounded.Comparisons[2] = model.BE, model.BE
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		lp       *model.LinearProgram
+		opts     SolveOptions
+		status   Status
+		sentinel error
+	}{
+		{"Infeasible", context.Background(), infeasible, SolveOptions{}, Infeasible, ErrInfeasible},
+		{"InfeasibleInteriorPoint", context.Background(), infeasible, SolveOptions{Algorithm: InteriorPoint}, Infeasible, ErrInfeasible},
+		{"Unbounded", context.Background(), unbounded, SolveOptions{}, Unbounded, ErrUnbounded},
+		{"UnboundedRevised", context.Background(), unbounded, SolveOptions{Algorithm: RevisedSimplex}, Unbounded, ErrUnbounded},
+		{"IterationLimit", context.Background(), parametricProblem(), SolveOptions{MaxIterations: 1}, IterationLimit, ErrIterationLimit},
+		{"Canceled", canceled, parametricProblem(), SolveOptions{}, Canceled, ErrCanceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SolveWithOptions(tt.ctx, tt.lp, tt.opts)
+			if !errors.Is(err, tt.sentinel) {
+				t.Fatalf("Expected an error matching %v, got %v", tt.sentinel, err)
+			}
+			if result == nil || result.Status != tt.status {
+				t.Fatalf("Expected the status %v, got %+v", tt.status, result)
+			}
+			if result.ObjVar != nil || result.Values != nil {
+				t.Errorf("Expected no solution, got %+v", result)
+			}
+			if _, err := result.GetSolutionJSON(); err == nil {
+				t.Errorf("Expected GetSolutionJSON() to fail without a solution")
+			}
+		})
+	}
+
+	// A solve that cannot start has no status
+	result, err := SolveWithOptions(context.Background(), parametricProblem(), SolveOptions{Algorithm: RevisedSimplex, Initialization: BigM})
+	if err == nil || result != nil {
+		t.Errorf("Expected an error without result, got %+v and %v", result, err)
+	}
+	if _, ok := statusOf(err); ok {
+		t.Errorf("Expected no status for %v", err)
+	}
+}
@@ -0,0 +1,424 @@
+package solver
+
+import (
+	"math"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// RefactorizationInterval is the number of eta updates after which the basis is factorized again.
+const RefactorizationInterval = 50
+
+// eta is the elementary update applied to the basis inverse after a pivot.
+type eta struct {
+	row    int
+	column []float64 // B^-1 * entering column at the time of the pivot
+}
+
+// RevisedSimplexSolver solves a linear program in slack form while only keeping
+// an LU factorization of the basis, instead of the full simplex tableau.
+type RevisedSimplexSolver struct {
+	numRows        int
+	numCols        int                  // variables + slacks, artificial columns come after them
+	columns        []model.SparseVector // constraint matrix stored by column
+	rhs            []float64
+	cost           []float64 // objective coefficients to maximize
+	artificialRows []int     // row of each artificial column -e_i
+	basis          []int     // basic column of each row
+	basicRow       []int     // row of each basic column, -1 for nonbasic columns
+	values         []float64 // values of the basic variables
+	upper          []float64 // upper bound of each column
+	atUpper        []bool    // nonbasic columns sitting at their upper bound instead of 0
+	lu             *LUFactorization
+	etas           []eta
+	limits         *limits // nil never stops the solve
+
+	pricing      Pricing   // Entering variable rule, Bland's rule by default
+	weights      []float64 // Devex reference weights or steepest edge norms of the columns
+	partialStart int       // First column of the next segment priced by PartialPricing
+	candidates   []int     // Improving columns kept by PartialPricing
+}
+
+// Initialize builds the revised simplex state from a linear program in slack form.
+// The starting basis is made of the slack variables, or of artificial variables for rows with a negative RHS.
+func (rs *RevisedSimplexSolver) Initialize(problem *model.LinearProgram) error {
+	if problem.State != model.Slack {
+		problem.ToSlackForm()
+	}
+
+	m := problem.NbConstraints
+	n := problem.NbVariables
+	n_orig := n - m
+
+	rs.numRows = m
+	rs.numCols = n
+	rs.columns = problem.ConstraintCoeff.Transpose().Rows
+	rs.rhs = make([]float64, m)
+	copy(rs.rhs, problem.Rhs)
+	rs.cost = make([]float64, n)
+	copy(rs.cost, problem.ObjCoeff)
+
+	rs.artificialRows = nil
+	rs.basis = make([]int, m)
+	for i := 0; i < m; i++ {
+		if rs.rhs[i] < 0 {
+			rs.basis[i] = n + len(rs.artificialRows)
+			rs.artificialRows = append(rs.artificialRows, i)
+		} else {
+			rs.basis[i] = n_orig + i
+		}
+	}
+	rs.cost = append(rs.cost, make([]float64, len(rs.artificialRows))...)
+
+	rs.basicRow = make([]int, n+len(rs.artificialRows))
+	rs.upper = make([]float64, n+len(rs.artificialRows))
+	rs.atUpper = make([]bool, n+len(rs.artificialRows))
+	for j := range rs.basicRow {
+		rs.basicRow[j] = -1
+		rs.upper[j] = math.Inf(1)
+		if j < n {
+			_, rs.upper[j] = problem.Bounds(j)
+		}
+	}
+	for i, col := range rs.basis {
+		rs.basicRow[col] = i
+	}
+
+	return rs.Refactorize()
+}
+
+// column returns the dense constraint column of the variable, including artificial ones.
+func (rs *RevisedSimplexSolver) column(j int) []float64 {
+	if j < rs.numCols {
+		return rs.columns[j].Dense(rs.numRows)
+	}
+	col := make([]float64, rs.numRows)
+	col[rs.artificialRows[j-rs.numCols]] = -1
+	return col
+}
+
+// isArtificial reports whether the column holds an artificial variable.
+func (rs *RevisedSimplexSolver) isArtificial(j int) bool {
+	return j >= rs.numCols
+}
+
+// Refactorize computes a fresh LU factorization of the basis, drops the eta file
+// and recomputes the values of the basic variables.
+func (rs *RevisedSimplexSolver) Refactorize() error {
+	b := make([]model.SparseVector, rs.numRows)
+	for k, col := range rs.basis {
+		if rs.isArtificial(col) {
+			b[k] = model.SparseVector{Indices: []int{rs.artificialRows[col-rs.numCols]}, Values: []float64{-1}}
+			continue
+		}
+		b[k] = rs.columns[col]
+	}
+
+	lu, err := FactorizeSparse(rs.numRows, b)
+	if err != nil {
+		return err
+	}
+	rs.lu = lu
+	rs.etas = rs.etas[:0]
+
+	// x_B = B^-1 * (b - sum of the columns at their upper bound times that bound)
+	rhs := make([]float64, rs.numRows)
+	copy(rhs, rs.rhs)
+	for j, atUpper := range rs.atUpper {
+		if atUpper {
+			column := rs.columns[j]
+			for p, i := range column.Indices {
+				rhs[i] -= rs.upper[j] * column.Values[p]
+			}
+		}
+	}
+	rs.values = rs.lu.Solve(rhs)
+	return nil
+}
+
+// Ftran returns B^-1 * a for the current basis B.
+func (rs *RevisedSimplexSolver) Ftran(a []float64) []float64 {
+	v := rs.lu.Solve(a)
+	for _, e := range rs.etas {
+		pivot := v[e.row] / e.column[e.row]
+		for i := range v {
+			v[i] -= e.column[i] * pivot
+		}
+		v[e.row] = pivot
+	}
+	return v
+}
+
+// Btran returns y such that y^T = c^T * B^-1 for the current basis B.
+func (rs *RevisedSimplexSolver) Btran(c []float64) []float64 {
+	w := make([]float64, len(c))
+	copy(w, c)
+	for k := len(rs.etas) - 1; k >= 0; k-- {
+		e := rs.etas[k]
+		sum := w[e.row]
+		for i := range w {
+			if i != e.row {
+				sum -= w[i] * e.column[i]
+			}
+		}
+		w[e.row] = sum / e.column[e.row]
+	}
+	return rs.lu.SolveTranspose(w)
+}
+
+// FindEnteringVariable prices the nonbasic columns against the given costs and returns the first one
+// that improves the objective (Bland's rule), or -1 if the basis is optimal. A column at its lower bound
+// improves with a positive reduced cost, a column at its upper bound with a negative one.
+func (rs *RevisedSimplexSolver) FindEnteringVariable(cost []float64) int {
+	y := rs.duals(cost)
+	for j := 0; j < rs.numCols; j++ {
+		if rs.improvement(cost, y, j) > 0 {
+			return j
+		}
+	}
+	return -1
+}
+
+// improvement returns the rate at which the nonbasic column j improves the objective for the given costs and
+// duals y, the absolute value of its reduced cost, or 0 if the column is basic or does not improve it.
+func (rs *RevisedSimplexSolver) improvement(cost, y []float64, j int) float64 {
+	epsilon := 1e-10
+	if rs.basicRow[j] != -1 {
+		return 0
+	}
+	reducedCost := cost[j] - rs.columns[j].Dot(y)
+	if rs.atUpper[j] {
+		reducedCost = -reducedCost
+	}
+	if reducedCost > epsilon {
+		return reducedCost
+	}
+	return 0
+}
+
+// FindLeavingVariable performs the minimum ratio test on the entering column alpha = B^-1 * a, when the
+// entering variable moves in the given direction (+1 up from 0, -1 down from its upper bound).
+// It returns the pivot row, the step length and whether the leaving variable reaches its upper bound.
+// When ratios are tied, it selects the one with the smallest row index (Bland's rule).
+func (rs *RevisedSimplexSolver) FindLeavingVariable(alpha []float64, direction float64) (int, float64, bool) {
+	smallestRatio := math.Inf(1)
+	pivotRow := -1
+	leavesAtUpper := false
+	epsilon := 1e-10
+
+	for i := 0; i < rs.numRows; i++ {
+		rate := -direction * alpha[i] // change of the basic variable per unit step
+		ratio, toUpper := math.Inf(1), false
+		if rate < -epsilon {
+			ratio = rs.values[i] / -rate
+		} else if rate > epsilon && !math.IsInf(rs.upper[rs.basis[i]], 1) {
+			ratio, toUpper = (rs.upper[rs.basis[i]]-rs.values[i])/rate, true
+		} else {
+			continue
+		}
+		if ratio < smallestRatio-epsilon {
+			smallestRatio = math.Max(ratio, 0)
+			pivotRow = i
+			leavesAtUpper = toUpper
+		}
+	}
+
+	return pivotRow, smallestRatio, leavesAtUpper
+}
+
+// PerformPivot changes the entering variable by theta, replaces the basic variable of pivotRow
+// by the entering column and updates the basis factorization.
+func (rs *RevisedSimplexSolver) PerformPivot(pivotRow, pivotCol int, alpha []float64, theta float64, leavesAtUpper bool) error {
+	if rs.weights != nil {
+		rs.updateWeights(pivotRow, pivotCol, alpha)
+	}
+
+	enteringValue := theta
+	if rs.atUpper[pivotCol] {
+		enteringValue += rs.upper[pivotCol]
+	}
+	for i := range rs.values {
+		rs.values[i] -= theta * alpha[i]
+	}
+	rs.values[pivotRow] = enteringValue
+
+	leaving := rs.basis[pivotRow]
+	rs.basicRow[leaving] = -1
+	rs.atUpper[leaving] = leavesAtUpper
+	rs.basis[pivotRow] = pivotCol
+	rs.basicRow[pivotCol] = pivotRow
+	rs.atUpper[pivotCol] = false
+
+	rs.etas = append(rs.etas, eta{row: pivotRow, column: alpha})
+	if len(rs.etas) >= RefactorizationInterval {
+		return rs.Refactorize()
+	}
+	return nil
+}
+
+// optimize runs revised simplex iterations for the given costs until the basis is optimal. Like the tableau
+// simplex, it falls back to Bland's rule after DegeneratePivotLimit degenerate pivots in a row.
+func (rs *RevisedSimplexSolver) optimize(cost []float64) error {
+	epsilon := 1e-10
+	degeneratePivots := 0
+
+	for {
+		pivotCol := rs.selectEntering(cost, degeneratePivots >= DegeneratePivotLimit)
+		if pivotCol == -1 {
+			return nil
+		}
+		if err := rs.limits.step(); err != nil {
+			return err
+		}
+
+		direction := 1.0
+		if rs.atUpper[pivotCol] {
+			direction = -1.0
+		}
+		alpha := rs.Ftran(rs.column(pivotCol))
+		pivotRow, step, leavesAtUpper := rs.FindLeavingVariable(alpha, direction)
+
+		// The entering variable reaches its other bound first: flip it without a pivot
+		upper := rs.upper[pivotCol]
+		if !math.IsInf(upper, 1) && upper <= step {
+			if upper > epsilon {
+				degeneratePivots = 0
+			}
+			for i := range rs.values {
+				rs.values[i] -= direction * upper * alpha[i]
+			}
+			rs.atUpper[pivotCol] = !rs.atUpper[pivotCol]
+			continue
+		}
+
+		if pivotRow == -1 {
+			// Moving the entering variable moves the basic variables by -alpha, per unit
+			ray := make([]float64, rs.numCols)
+			ray[pivotCol] = direction
+			for i, col := range rs.basis {
+				if col < rs.numCols {
+					ray[col] = -direction * alpha[i]
+				}
+			}
+			return &UnboundedError{Ray: ray}
+		}
+
+		if step > epsilon {
+			degeneratePivots = 0
+		} else {
+			degeneratePivots++
+		}
+		err := rs.PerformPivot(pivotRow, pivotCol, alpha, direction*step, leavesAtUpper)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// PhaseOne drives the artificial variables to zero, or proves that the problem is infeasible
+// with the Phase I duals as Farkas certificate.
+func (rs *RevisedSimplexSolver) PhaseOne() error {
+	if len(rs.artificialRows) == 0 {
+		return nil
+	}
+	epsilon := 1e-9
+
+	cost := make([]float64, len(rs.cost))
+	for k := range rs.artificialRows {
+		cost[rs.numCols+k] = -1
+	}
+
+	// Artificial columns are never priced, so they can only leave the basis
+	err := rs.optimize(cost)
+	if err != nil {
+		return err
+	}
+
+	for i, col := range rs.basis {
+		if rs.isArtificial(col) && rs.values[i] > epsilon {
+			return &InfeasibleError{Farkas: rs.duals(cost)}
+		}
+	}
+
+	return rs.driveOutArtificials()
+}
+
+// driveOutArtificials pivots the artificial variables that are still basic at zero level out of the basis.
+// Rows where this is impossible are redundant and keep their artificial variable.
+func (rs *RevisedSimplexSolver) driveOutArtificials() error {
+	epsilon := 1e-10
+
+	for i := 0; i < rs.numRows; i++ {
+		if !rs.isArtificial(rs.basis[i]) {
+			continue
+		}
+		unit := make([]float64, rs.numRows)
+		unit[i] = 1
+		rowOfInverse := rs.Btran(unit)
+		for j := 0; j < rs.numCols; j++ {
+			if rs.basicRow[j] == -1 && math.Abs(rs.columns[j].Dot(rowOfInverse)) > epsilon {
+				err := rs.PerformPivot(i, j, rs.Ftran(rs.column(j)), 0, false)
+				if err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// Solve runs Phase I if needed and then optimizes the original objective.
+func (rs *RevisedSimplexSolver) Solve() error {
+	rs.resetPricing()
+	err := rs.PhaseOne()
+	if err != nil {
+		return err
+	}
+	return rs.optimize(rs.cost)
+}
+
+// ExtractSolution returns the values of the original variables followed by the objective value.
+func (rs *RevisedSimplexSolver) ExtractSolution(problem *model.LinearProgram) []float64 {
+	numOrigVars := problem.NbVariables - problem.NbConstraints
+	solution := make([]float64, numOrigVars+1)
+
+	objectiveValue := 0.0
+	for j := 0; j < rs.numCols; j++ {
+		value := 0.0
+		if rs.basicRow[j] != -1 {
+			value = rs.values[rs.basicRow[j]]
+		} else if rs.atUpper[j] {
+			value = rs.upper[j]
+		}
+		if j < numOrigVars {
+			solution[j] = value
+		}
+		objectiveValue += rs.cost[j] * value
+	}
+	solution[numOrigVars] = objectiveValue
+
+	return solution
+}
+
+// ExtractDuals returns the dual value y = c_B * B^-1 of each row for the maximized objective.
+func (rs *RevisedSimplexSolver) ExtractDuals() []float64 {
+	return rs.duals(rs.cost)
+}
+
+// duals returns y = c_B * B^-1 for the given costs.
+func (rs *RevisedSimplexSolver) duals(cost []float64) []float64 {
+	costB := make([]float64, rs.numRows)
+	for i, col := range rs.basis {
+		costB[i] = cost[col]
+	}
+	return rs.Btran(costB)
+}
+
+func dot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
@@ -0,0 +1,159 @@
+package solver
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestSolveWithOptions_RevisedSimplex(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints: 3,
+		NbVariables:   2,
+		VariableNames: []string{"x1", "x2"},
+		Objective:     model.MAXIMIZE,
+		ObjCoeff:      []float64{3, 5},
+		Comparisons:   []model.Comparison{model.LE, model.LE, model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 0},
+			{0, 2},
+			{3, 2},
+		}),
+		Rhs: []float64{4, 12, 18},
+	}
+
+	result, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: RevisedSimplex})
+	if err != nil {
+		t.Fatalf("SolveWithOptions() error = %v", err)
+	}
+
+	expectedSolution := []float64{2, 6, 36}
+	if !equalFloat64Slices(result.ObjVar, expectedSolution, 1e-9) {
+		t.Errorf("Expected solution to be %v, got %v", expectedSolution, result.ObjVar)
+	}
+}
+
+func TestSolveWithOptions_RevisedSimplexPhaseOne(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints: 3,
+		NbVariables:   2,
+		VariableNames: []string{"x", "y"},
+		Objective:     model.MINIMIZE,
+		ObjCoeff:      []float64{2, 3},
+		Comparisons:   []model.Comparison{model.BE, model.LE, model.EQ},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 1},
+			{2, 1},
+			{1, -1},
+		}),
+		Rhs: []float64{10, 20, 2},
+	}
+
+	result, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: RevisedSimplex})
+	if err != nil {
+		t.Fatalf("SolveWithOptions() error = %v", err)
+	}
+
+	expectedSolution := []float64{6, 4, 24}
+	if !equalFloat64Slices(result.ObjVar, expectedSolution, 1e-9) {
+		t.Errorf("Expected solution to be %v, got %v", expectedSolution, result.ObjVar)
+	}
+}
+
+func TestSolveWithOptions_RevisedSimplexErrors(t *testing.T) {
+	t.Run("Unbounded", func(t *testing.T) {
+		lp := &model.LinearProgram{
+			NbConstraints:   2,
+			NbVariables:     2,
+			VariableNames:   []string{"x1", "x2"},
+			Objective:       model.MAXIMIZE,
+			ObjCoeff:        []float64{1, 1},
+			Comparisons:     []model.Comparison{model.LE, model.LE},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, -1}, {-1, 1}}),
+			Rhs:             []float64{1, 1},
+		}
+		_, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: RevisedSimplex})
+		if err == nil || err.Error() != "unbounded problem" {
+			t.Errorf("Expected error to be 'unbounded problem', got %v", err)
+		}
+	})
+
+	t.Run("Infeasible", func(t *testing.T) {
+		lp := &model.LinearProgram{
+			NbConstraints:   2,
+			NbVariables:     2,
+			VariableNames:   []string{"x1", "x2"},
+			Objective:       model.MAXIMIZE,
+			ObjCoeff:        []float64{1, 1},
+			Comparisons:     []model.Comparison{model.LE, model.BE},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}, {1, 1}}),
+			Rhs:             []float64{1, 2},
+		}
+		_, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: RevisedSimplex})
+		if err == nil || err.Error() != "infeasible problem" {
+			t.Errorf("Expected error to be 'infeasible problem', got %v", err)
+		}
+	})
+}
+
+func TestRevisedSimplex_MatchesTableau(t *testing.T) {
+	// Large enough to go through several refactorizations of the basis
+	rng := rand.New(rand.NewSource(1))
+	build := func() *model.LinearProgram {
+		rng.Seed(1)
+		m, n := 40, 60
+		lp := &model.LinearProgram{NbConstraints: m, NbVariables: n, Objective: model.MAXIMIZE}
+		for j := 0; j < n; j++ {
+			lp.VariableNames = append(lp.VariableNames, "x")
+			lp.ObjCoeff = append(lp.ObjCoeff, 1+rng.Float64()*9)
+			if j%3 == 0 {
+				lp.UpperBounds = append(lp.UpperBounds, 0.5)
+			} else {
+				lp.UpperBounds = append(lp.UpperBounds, math.Inf(1))
+			}
+		}
+		lp.ConstraintCoeff = model.NewSparseMatrix(m, n)
+		for i := 0; i < m; i++ {
+			for j := 0; j < n; j++ {
+				lp.ConstraintCoeff.Set(i, j, rng.Float64()*10)
+			}
+			if i%5 == 0 {
+				lp.Comparisons = append(lp.Comparisons, model.BE)
+				lp.Rhs = append(lp.Rhs, 10+rng.Float64()*10)
+			} else {
+				lp.Comparisons = append(lp.Comparisons, model.LE)
+				lp.Rhs = append(lp.Rhs, 50+rng.Float64()*50)
+			}
+		}
+		return lp
+	}
+
+	tableauLP := build()
+	tableauResult, err := SolveWithOptions(context.Background(), tableauLP, SolveOptions{Algorithm: PrimalSimplex})
+	if err != nil {
+		t.Fatalf("SolveWithOptions() error = %v", err)
+	}
+	revisedLP := build()
+	revisedResult, err := SolveWithOptions(context.Background(), revisedLP, SolveOptions{Algorithm: RevisedSimplex})
+	if err != nil {
+		t.Fatalf("SolveWithOptions() error = %v", err)
+	}
+
+	if !equalFloat64Slices(revisedResult.ObjVar, tableauResult.ObjVar, 1e-6) {
+		t.Errorf("Expected revised simplex solution %v to match the tableau solution %v", revisedResult.ObjVar, tableauResult.ObjVar)
+	}
+
+	// The interior-point method converges to an optimal point, not necessarily the same vertex
+	interiorPointLP := build()
+	interiorPointResult, err := SolveWithOptions(context.Background(), interiorPointLP, SolveOptions{Algorithm: InteriorPoint})
+	if err != nil {
+		t.Fatalf("SolveWithOptions() error = %v", err)
+	}
+	objective := len(tableauResult.ObjVar) - 1
+	if math.Abs(interiorPointResult.ObjVar[objective]-tableauResult.ObjVar[objective]) > 1e-5 {
+		t.Errorf("Expected interior-point objective %v to match the tableau objective %v", interiorPointResult.ObjVar[objective], tableauResult.ObjVar[objective])
+	}
+}
@@ -0,0 +1,192 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// geometricMeanPasses is the number of passes of GeometricMeanScaling over the rows and columns before the final
+// equilibration.
+const geometricMeanPasses = 4
+
+// ScaleFactors holds the factors applied by Scale. The scaled problem has the coefficients
+// Rows[i]*a_ij*Columns[j], the RHS Rows[i]*b_i and the objective coefficients c_j*Columns[j], so its variables are
+// the original ones divided by Columns[j].
+type ScaleFactors struct {
+	Rows    []float64
+	Columns []float64
+}
+
+// Scale returns a copy of the problem with its rows and columns scaled by powers of two, which keep the
+// coefficients exact, so that their magnitudes are close to one, together with the factors that map its solution
+// back. The objective value does not change. Integer variables keep their scale, so that they stay integral. The
+// problem must not be converted, and is left unchanged.
+func Scale(lp *model.LinearProgram, method ScalingMethod) (*model.LinearProgram, *ScaleFactors, error) {
+	if lp.State != model.Undefined {
+		return nil, nil, fmt.Errorf("scaling needs a problem that is not converted")
+	}
+
+	factors := &ScaleFactors{Rows: make([]float64, lp.NbConstraints), Columns: make([]float64, lp.NbVariables)}
+	for i := range factors.Rows {
+		factors.Rows[i] = 1
+	}
+	for j := range factors.Columns {
+		factors.Columns[j] = 1
+	}
+	columns := lp.ConstraintCoeff.Transpose()
+
+	switch method {
+	case NoScaling:
+	case EquilibrationScaling:
+		factors.equilibrate(lp, columns)
+	case GeometricMeanScaling:
+		for pass := 0; pass < geometricMeanPasses; pass++ {
+			factors.scaleRows(lp.ConstraintCoeff, func(min, max float64) float64 { return math.Sqrt(min * max) })
+			factors.scaleColumns(lp, columns, func(min, max float64) float64 { return math.Sqrt(min * max) })
+		}
+		factors.equilibrate(lp, columns)
+	default:
+		return nil, nil, fmt.Errorf("unknown scaling method %d", method)
+	}
+
+	scaled := lp.Clone()
+	for i, row := range scaled.ConstraintCoeff.Rows {
+		for k, j := range row.Indices {
+			row.Values[k] *= factors.Rows[i] * factors.Columns[j]
+		}
+		scaled.Rhs[i] *= factors.Rows[i]
+	}
+	scaled.LowerBounds = make([]float64, lp.NbVariables)
+	scaled.UpperBounds = make([]float64, lp.NbVariables)
+	for j, factor := range factors.Columns {
+		scaled.ObjCoeff[j] *= factor
+		lower, upper := lp.Bounds(j)
+		scaled.LowerBounds[j], scaled.UpperBounds[j] = lower/factor, upper/factor
+	}
+	scaled.Signs = nil // The bounds are explicit
+	return scaled, factors, nil
+}
+
+// equilibrate divides every row, then every column, by its largest scaled coefficient.
+func (f *ScaleFactors) equilibrate(lp *model.LinearProgram, columns *model.SparseMatrix) {
+	f.scaleRows(lp.ConstraintCoeff, func(min, max float64) float64 { return max })
+	f.scaleColumns(lp, columns, func(min, max float64) float64 { return max })
+}
+
+// scaleRows divides every nonempty row by the size returned for its smallest and largest scaled coefficient,
+// rounded to a power of two.
+func (f *ScaleFactors) scaleRows(matrix *model.SparseMatrix, size func(min, max float64) float64) {
+	for i, row := range matrix.Rows {
+		if min, max, ok := f.extremes(row, func(j int) float64 { return f.Rows[i] * f.Columns[j] }); ok {
+			f.Rows[i] = powerOfTwo(f.Rows[i] / size(min, max))
+		}
+	}
+}
+
+// scaleColumns divides every nonempty continuous column like scaleRows.
+func (f *ScaleFactors) scaleColumns(lp *model.LinearProgram, columns *model.SparseMatrix, size func(min, max float64) float64) {
+	for j, column := range columns.Rows {
+		if lp.IsInteger(j) {
+			continue
+		}
+		if min, max, ok := f.extremes(column, func(i int) float64 { return f.Rows[i] * f.Columns[j] }); ok {
+			f.Columns[j] = powerOfTwo(f.Columns[j] / size(min, max))
+		}
+	}
+}
+
+// extremes returns the smallest and largest magnitude of the nonzero entries of the vector, each one multiplied by
+// its factor, and false if there is none.
+func (f *ScaleFactors) extremes(v model.SparseVector, factor func(index int) float64) (float64, float64, bool) {
+	min, max := math.Inf(1), 0.0
+	for k, index := range v.Indices {
+		if v.Values[k] == 0 {
+			continue
+		}
+		magnitude := math.Abs(v.Values[k]) * factor(index)
+		min, max = math.Min(min, magnitude), math.Max(max, magnitude)
+	}
+	return min, max, max > 0
+}
+
+// powerOfTwo rounds a positive factor to the nearest power of two.
+func powerOfTwo(factor float64) float64 {
+	return math.Exp2(math.Round(math.Log2(factor)))
+}
+
+// Unscale sets the solution of the original problem from the Result of the scaled problem: ObjVar, the Duals, Slacks and
+// ReducedCosts when the solve computed them, the sensitivity ranges and the basis, which fits both problems.
+func (f *ScaleFactors) Unscale(lp *model.LinearProgram, scaled *Result) {
+	lp.ObjVar = make([]float64, len(scaled.ObjVar))
+	copy(lp.ObjVar, scaled.ObjVar)
+	for j, factor := range f.Columns {
+		lp.ObjVar[j] *= factor
+	}
+	lp.Basis = scaled.Basis
+	lp.AlternativeOptima = scaled.AlternativeOptima
+
+	lp.Duals, lp.Slacks, lp.ReducedCosts = nil, nil, nil
+	if scaled.Duals != nil {
+		lp.Duals = make([]float64, len(f.Rows))
+		lp.Slacks = make([]float64, len(f.Rows))
+		for i, factor := range f.Rows {
+			lp.Duals[i] = scaled.Duals[i] * factor
+			lp.Slacks[i] = scaled.Slacks[i] / factor
+		}
+		lp.ReducedCosts = make([]float64, len(f.Columns))
+		for j, factor := range f.Columns {
+			lp.ReducedCosts[j] = scaled.ReducedCosts[j] / factor
+		}
+	}
+
+	lp.Sensitivity = nil
+	if scaled.Sensitivity != nil {
+		lp.Sensitivity = &model.Sensitivity{
+			ObjCoeff: make([]model.Range, len(f.Columns)),
+			Rhs:      make([]model.Range, len(f.Rows)),
+		}
+		for j, factor := range f.Columns {
+			r := scaled.Sensitivity.ObjCoeff[j]
+			lp.Sensitivity.ObjCoeff[j] = model.Range{Lower: r.Lower / factor, Upper: r.Upper / factor}
+		}
+		for i, factor := range f.Rows {
+			r := scaled.Sensitivity.Rhs[i]
+			lp.Sensitivity.Rhs[i] = model.Range{Lower: r.Lower / factor, Upper: r.Upper / factor}
+		}
+	}
+}
+
+// unscaleCertificate maps the certificate carried by an error of the scaled problem back to the original one: the
+// multipliers of the scaled rows and the directions of the scaled variables.
+func (f *ScaleFactors) unscaleCertificate(err error) error {
+	var infeasible *InfeasibleError
+	if errors.As(err, &infeasible) && infeasible.Farkas != nil {
+		for i, factor := range f.Rows {
+			infeasible.Farkas[i] *= factor
+		}
+	}
+	var unbounded *UnboundedError
+	if errors.As(err, &unbounded) && unbounded.Ray != nil {
+		for j, factor := range f.Columns {
+			unbounded.Ray[j] *= factor
+		}
+	}
+	return err
+}
+
+// solveScaled solves a scaled copy of the problem and unscales its solution.
+func solveScaled(lp *model.LinearProgram, opts SolveOptions, lim *limits) error {
+	scaled, factors, err := Scale(lp, opts.Scaling)
+	if err != nil {
+		return err
+	}
+	opts.Scaling = NoScaling
+	if err := solve(scaled, opts, lim); err != nil {
+		return factors.unscaleCertificate(err)
+	}
+	factors.Unscale(lp, solvedResult(scaled))
+	return nil
+}
@@ -0,0 +1,179 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// badlyScaledProblem is parametricProblem with x measured in units of 1e4, y in units of 1e-3 and the rows
+// multiplied by 1e-11, 1e6 and 1e-6. Its optimum is x = 2e-4, y = 6000 with the objective 36.
+func badlyScaledProblem() *model.LinearProgram {
+	return &model.LinearProgram{
+		NbConstraints: 3,
+		NbVariables:   2,
+		VariableNames: []string{"x", "y"},
+		Objective:     model.MAXIMIZE,
+		ObjCoeff:      []float64{3e4, 5e-3},
+		Comparisons:   []model.Comparison{model.LE, model.LE, model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1e-7, 0},
+			{0, 2e3},
+			{3e-2, 2e-9},
+		}),
+		Rhs: []float64{4e-11, 12e6, 18e-6},
+	}
+}
+
+func TestScaling_BadlyScaled(t *testing.T) {
+	methods := map[string]ScalingMethod{"Equilibration": EquilibrationScaling, "GeometricMean": GeometricMeanScaling}
+	algorithms := map[string]Algorithm{"PrimalSimplex": PrimalSimplex, "RevisedSimplex": RevisedSimplex}
+
+	for methodName, method := range methods {
+		for name, algorithm := range algorithms {
+			t.Run(methodName+"/"+name, func(t *testing.T) {
+				// The entries 1e-7 and 2e-9 fall under the tolerances of the ratio test unless the problem is scaled
+				lp := badlyScaledProblem()
+				result, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: algorithm, Scaling: method})
+				if err != nil {
+					t.Fatalf("SolveWithOptions() error = %v", err)
+				}
+				if !equalRelative(result.ObjVar, []float64{2e-4, 6000, 36}) {
+					t.Errorf("Expected [0.0002 6000 36], got %v", result.ObjVar)
+				}
+				if !equalRelative(result.Duals, []float64{0, 1.5e-6, 1e6}) {
+					t.Errorf("Expected the duals [0 1.5e-06 1e+06], got %v", result.Duals)
+				}
+				if !equalRelative(result.Slacks, []float64{2e-11, 0, 0}) {
+					t.Errorf("Expected the slacks [2e-11 0 0], got %v", result.Slacks)
+				}
+				if !reflect.DeepEqual(lp.ConstraintCoeff, badlyScaledProblem().ConstraintCoeff) {
+					t.Errorf("Expected the problem to stay unscaled")
+				}
+			})
+		}
+	}
+}
+
+func TestScaling_SameSolution(t *testing.T) {
+	// Scaling a well-scaled problem changes nothing in its solution, ranges and basis
+	expected := parametricProblem()
+	expectedResult, err := Solve(expected)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+
+	for _, method := range []ScalingMethod{EquilibrationScaling, GeometricMeanScaling} {
+		lp := parametricProblem()
+		result, err := SolveWithOptions(context.Background(), lp, SolveOptions{Scaling: method})
+		if err != nil {
+			t.Fatalf("SolveWithOptions() error = %v", err)
+		}
+		for _, pair := range [][2][]float64{
+			{result.ObjVar, expectedResult.ObjVar}, {result.Duals, expectedResult.Duals},
+			{result.Slacks, expectedResult.Slacks}, {result.ReducedCosts, expectedResult.ReducedCosts},
+		} {
+			if !equalFloat64Slices(pair[0], pair[1], 1e-9) {
+				t.Errorf("Method %d: expected %v, got %v", method, pair[1], pair[0])
+			}
+		}
+		if !reflect.DeepEqual(result.Sensitivity, expectedResult.Sensitivity) {
+			t.Errorf("Method %d: expected the ranges %+v, got %+v", method, expectedResult.Sensitivity, result.Sensitivity)
+		}
+		if !reflect.DeepEqual(result.Basis, expectedResult.Basis) {
+			t.Errorf("Method %d: expected the basis %+v, got %+v", method, expectedResult.Basis, result.Basis)
+		}
+	}
+}
+
+func TestScale(t *testing.T) {
+	lp := badlyScaledProblem()
+	lp.Types = []model.VariableType{model.Continuous, model.Integer}
+	scaled, factors, err := Scale(lp, EquilibrationScaling)
+	if err != nil {
+		t.Fatalf("Scale() error = %v", err)
+	}
+	if factors.Columns[1] != 1 {
+		t.Errorf("Expected the integer column to keep its scale, got %v", factors.Columns[1])
+	}
+	for _, factor := range append(append([]float64(nil), factors.Rows...), factors.Columns...) {
+		if _, exponent := math.Frexp(factor); factor != math.Ldexp(0.5, exponent) {
+			t.Errorf("Expected powers of two, got %v", factor)
+		}
+	}
+	// The continuous column is equilibrated, its largest coefficient lies within a factor sqrt(2) of one
+	column := scaled.ConstraintCoeff.Transpose().Rows[0]
+	largest := 0.0
+	for _, value := range column.Values {
+		largest = math.Max(largest, math.Abs(value))
+	}
+	if largest < math.Sqrt2/2 || largest > math.Sqrt2 {
+		t.Errorf("Expected the largest coefficient of x near 1, got %v", largest)
+	}
+
+	if _, _, err := Scale(lp, ScalingMethod(42)); err == nil {
+		t.Errorf("Expected an error for an unknown method")
+	}
+}
+
+func TestScaling_Certificates(t *testing.T) {
+	// x + y <= 2 contradicts 1000x + 1000y >= 5000
+	infeasible := &model.LinearProgram{
+		NbConstraints: 2,
+		NbVariables:   2,
+		VariableNames: []string{"x", "y"},
+		Objective:     model.MAXIMIZE,
+		ObjCoeff:      []float64{1, 1},
+		Comparisons:   []model.Comparison{model.LE, model.BE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 1},
+			{1000, 1000},
+		}),
+		Rhs: []float64{2, 5000},
+	}
+	_, err := SolveWithOptions(context.Background(), infeasible.Clone(), SolveOptions{Scaling: GeometricMeanScaling})
+	var infeasibleErr *InfeasibleError
+	if !errors.As(err, &infeasibleErr) {
+		t.Fatalf("Expected an InfeasibleError, got %v", err)
+	}
+	if err := CheckCertificate(infeasible, err); err != nil {
+		t.Errorf("CheckCertificate() error = %v", err)
+	}
+
+	// maximize x - y subject to -1000x + 1000y <= 1000 grows without limit along x
+	unbounded := &model.LinearProgram{
+		NbConstraints:   1,
+		NbVariables:     2,
+		VariableNames:   []string{"x", "y"},
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        []float64{1, -1},
+		Comparisons:     []model.Comparison{model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{-1000, 1000}}),
+		Rhs:             []float64{1000},
+	}
+	_, err = SolveWithOptions(context.Background(), unbounded.Clone(), SolveOptions{Scaling: EquilibrationScaling})
+	var unboundedErr *UnboundedError
+	if !errors.As(err, &unboundedErr) {
+		t.Fatalf("Expected an UnboundedError, got %v", err)
+	}
+	if err := CheckCertificate(unbounded, err); err != nil {
+		t.Errorf("CheckCertificate() error = %v", err)
+	}
+}
+
+// equalRelative reports whether the slices are equal up to a relative tolerance of 1e-9 of the larger magnitude.
+func equalRelative(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-9*math.Max(math.Abs(a[i]), math.Abs(b[i])) {
+			return false
+		}
+	}
+	return true
+}
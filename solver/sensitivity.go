@@ -0,0 +1,239 @@
+package solver
+
+import (
+	"math"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// Sensitivity computes the ranging of the optimal solution held by the final tableau: for each objective
+// coefficient the values for which the basis stays optimal, and for each right-hand side the values for
+// which it stays feasible. Both are given for the original variables and constraints of the problem.
+func (table *SimplexTable) Sensitivity(problem *model.LinearProgram) *model.Sensitivity {
+	numOrigVars := problem.NbVariables - len(problem.SplitVariables) - len(problem.SlackVariablesNames)
+	objectiveSign := 1.0
+	if problem.NegatedObjective {
+		objectiveSign = -1
+	}
+
+	firstSlack := problem.NbVariables - problem.NbConstraints
+	fixed := equalitySlacks(problem, len(table.data[0])-1)
+
+	sensitivity := &model.Sensitivity{}
+	for j := 0; j < numOrigVars; j++ {
+		delta := make([]float64, len(table.data[0])-1)
+		columns, signs := problem.OriginalColumns(j)
+		for k, col := range columns {
+			delta[col] = objectiveSign * signs[k]
+		}
+		lower, upper := table.costRange(delta, fixed)
+		coeff := problem.OriginalObjCoeff(j)
+		sensitivity.ObjCoeff = append(sensitivity.ObjCoeff, model.Range{Lower: coeff + lower, Upper: coeff + upper})
+	}
+
+	for i := 0; i < problem.NbOriginalConstraints(); i++ {
+		// Moving a RHS moves the basic variables along the tableau columns of the slacks of its rows
+		direction := make([]float64, len(table.basicVariables))
+		rows, signs := problem.OriginalRows(i)
+		for k, row := range rows {
+			for r := range direction {
+				direction[r] += signs[k] * table.data[r][firstSlack+row]
+			}
+		}
+		lower, upper := table.rhsRange(direction)
+		rhs := problem.OriginalRhs(i)
+		sensitivity.Rhs = append(sensitivity.Rhs, model.Range{Lower: rhs + lower, Upper: rhs + upper})
+	}
+
+	return sensitivity
+}
+
+// equalitySlacks marks, among the given number of columns, the slacks of the two rows of every equality. They
+// always sum to zero, so they cannot enter the basis.
+func equalitySlacks(problem *model.LinearProgram, numCols int) []bool {
+	firstSlack := problem.NbVariables - problem.NbConstraints
+	fixed := make([]bool, numCols)
+	for i := 0; i < problem.NbOriginalConstraints(); i++ {
+		if rows, _ := problem.OriginalRows(i); len(rows) > 1 {
+			for _, row := range rows {
+				fixed[firstSlack+row] = true
+			}
+		}
+	}
+	return fixed
+}
+
+// costRange returns the interval of t for which the basis stays optimal when the maximized objective
+// coefficient of every column j changes by t*delta[j]. Fixed columns are ignored.
+func (table *SimplexTable) costRange(delta []float64, fixed []bool) (float64, float64) {
+	objectiveRow := len(table.data) - 1
+	epsilon := 1e-10
+
+	// A complemented column holds x' = u - x, whose coefficient moves the other way
+	columnDelta := func(col int) float64 {
+		if col < len(table.atUpper) && table.atUpper[col] {
+			return -delta[col]
+		}
+		return delta[col]
+	}
+
+	basic := make([]bool, len(delta))
+	for _, col := range table.basicVariables {
+		basic[int(col)] = true
+	}
+
+	lower, upper := math.Inf(-1), math.Inf(1)
+	for k := range delta {
+		if basic[k] || fixed[k] || table.isArtificial(k) {
+			continue
+		}
+		// Rate of change of the reduced cost of column k
+		rate := -columnDelta(k)
+		for r, col := range table.basicVariables {
+			rate += columnDelta(int(col)) * table.data[r][k]
+		}
+		reducedCost := math.Max(table.data[objectiveRow][k], 0)
+		if rate > epsilon {
+			lower = math.Max(lower, -reducedCost/rate)
+		} else if rate < -epsilon {
+			upper = math.Min(upper, reducedCost/-rate)
+		}
+	}
+	return lower, upper
+}
+
+// rhsRange returns the interval of t for which the basic variables stay within their bounds
+// when they move by t*direction.
+func (table *SimplexTable) rhsRange(direction []float64) (float64, float64) {
+	rhsCol := len(table.data[0]) - 1
+	epsilon := 1e-10
+
+	lower, upper := math.Inf(-1), math.Inf(1)
+	for r, col := range table.basicVariables {
+		value := math.Max(table.data[r][rhsCol], 0)
+		bound := table.upperBound(int(col))
+		if table.isArtificial(int(col)) {
+			bound = 0 // Artificial variables left in the basis must stay at zero
+		}
+		bound = math.Max(bound, value)
+
+		rate := direction[r]
+		if rate > epsilon {
+			lower = math.Max(lower, -value/rate)
+			upper = math.Min(upper, (bound-value)/rate)
+		} else if rate < -epsilon {
+			upper = math.Min(upper, -value/rate)
+			lower = math.Max(lower, (bound-value)/rate)
+		}
+	}
+	return lower, upper
+}
+
+// Sensitivity computes the same ranging as SimplexTable.Sensitivity from the final basis of the revised simplex.
+// The rows and columns of the tableau it needs are computed from the LU factorization of the basis.
+func (rs *RevisedSimplexSolver) Sensitivity(problem *model.LinearProgram) *model.Sensitivity {
+	numOrigVars := problem.NbVariables - len(problem.SplitVariables) - len(problem.SlackVariablesNames)
+	objectiveSign := 1.0
+	if problem.NegatedObjective {
+		objectiveSign = -1
+	}
+	firstSlack := problem.NbVariables - problem.NbConstraints
+	fixed := equalitySlacks(problem, rs.numCols)
+
+	// Reduced costs c_k - y*a_k of the nonbasic columns, non-positive at the lower bound and non-negative at the
+	// upper bound for an optimal basis
+	y := rs.ExtractDuals()
+	reducedCosts := make([]float64, rs.numCols)
+	for k := range reducedCosts {
+		if rs.basicRow[k] == -1 {
+			reducedCosts[k] = rs.cost[k] - rs.columns[k].Dot(y)
+		}
+	}
+
+	sensitivity := &model.Sensitivity{}
+	for j := 0; j < numOrigVars; j++ {
+		delta := make([]float64, len(rs.cost))
+		columns, signs := problem.OriginalColumns(j)
+		for k, col := range columns {
+			delta[col] = objectiveSign * signs[k]
+		}
+		lower, upper := rs.costRange(delta, reducedCosts, fixed)
+		coeff := problem.OriginalObjCoeff(j)
+		sensitivity.ObjCoeff = append(sensitivity.ObjCoeff, model.Range{Lower: coeff + lower, Upper: coeff + upper})
+	}
+
+	for i := 0; i < problem.NbOriginalConstraints(); i++ {
+		// Moving a RHS moves the basic variables along B^-1 times the slack columns of its rows
+		change := make([]float64, rs.numRows)
+		rows, signs := problem.OriginalRows(i)
+		for k, row := range rows {
+			slack := rs.columns[firstSlack+row]
+			for p, r := range slack.Indices {
+				change[r] += signs[k] * slack.Values[p]
+			}
+		}
+		lower, upper := rs.rhsRange(rs.Ftran(change))
+		rhs := problem.OriginalRhs(i)
+		sensitivity.Rhs = append(sensitivity.Rhs, model.Range{Lower: rhs + lower, Upper: rhs + upper})
+	}
+
+	return sensitivity
+}
+
+// costRange returns the interval of t for which the basis stays optimal when the maximized cost of every column
+// k changes by t*delta[k]. Fixed columns are ignored.
+func (rs *RevisedSimplexSolver) costRange(delta, reducedCosts []float64, fixed []bool) (float64, float64) {
+	epsilon := 1e-10
+
+	deltaB := make([]float64, rs.numRows)
+	for i, col := range rs.basis {
+		deltaB[i] = delta[col]
+	}
+	z := rs.Btran(deltaB)
+
+	lower, upper := math.Inf(-1), math.Inf(1)
+	for k := 0; k < rs.numCols; k++ {
+		if rs.basicRow[k] != -1 || fixed[k] {
+			continue
+		}
+		// A column at its upper bound stays there while its reduced cost is non-negative
+		sign := 1.0
+		if rs.atUpper[k] {
+			sign = -1
+		}
+		slack := math.Max(-sign*reducedCosts[k], 0)
+		rate := sign * (delta[k] - rs.columns[k].Dot(z))
+		if rate > epsilon {
+			upper = math.Min(upper, slack/rate)
+		} else if rate < -epsilon {
+			lower = math.Max(lower, slack/rate)
+		}
+	}
+	return lower, upper
+}
+
+// rhsRange returns the interval of t for which the basic variables stay within their bounds when they move by
+// t*direction.
+func (rs *RevisedSimplexSolver) rhsRange(direction []float64) (float64, float64) {
+	epsilon := 1e-10
+
+	lower, upper := math.Inf(-1), math.Inf(1)
+	for r, col := range rs.basis {
+		value := math.Max(rs.values[r], 0)
+		bound := rs.upper[col]
+		if rs.isArtificial(col) {
+			bound = 0 // Artificial variables left in the basis must stay at zero
+		}
+		bound = math.Max(bound, value)
+
+		rate := direction[r]
+		if rate > epsilon {
+			lower = math.Max(lower, -value/rate)
+			upper = math.Min(upper, (bound-value)/rate)
+		} else if rate < -epsilon {
+			upper = math.Min(upper, -value/rate)
+			lower = math.Max(lower, (bound-value)/rate)
+		}
+	}
+	return lower, upper
+}
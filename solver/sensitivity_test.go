@@ -0,0 +1,161 @@
+package solver
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func equalRanges(a, b []model.Range, tolerance float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		for _, pair := range [][2]float64{{a[i].Lower, b[i].Lower}, {a[i].Upper, b[i].Upper}} {
+			if math.IsInf(pair[0], 0) || math.IsInf(pair[1], 0) {
+				if pair[0] != pair[1] {
+					return false
+				}
+			} else if math.Abs(pair[0]-pair[1]) > tolerance {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestSensitivity(t *testing.T) {
+	inf := math.Inf(1)
+	tests := []struct {
+		name     string
+		lp       *model.LinearProgram
+		opts     SolveOptions
+		objCoeff []model.Range
+		rhs      []model.Range
+	}{
+		{
+			name: "Maximize",
+			lp: &model.LinearProgram{
+				NbConstraints: 3,
+				NbVariables:   2,
+				VariableNames: []string{"x1", "x2"},
+				Objective:     model.MAXIMIZE,
+				ObjCoeff:      []float64{3, 5},
+				Comparisons:   []model.Comparison{model.LE, model.LE, model.LE},
+				ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+					{1, 0},
+					{0, 2},
+					{3, 2},
+				}),
+				Rhs: []float64{4, 12, 18},
+			},
+			objCoeff: []model.Range{{Lower: 0, Upper: 7.5}, {Lower: 2, Upper: inf}},
+			rhs:      []model.Range{{Lower: 2, Upper: inf}, {Lower: 6, Upper: 18}, {Lower: 12, Upper: 24}},
+		},
+		{
+			name: "VariableAtUpperBound",
+			lp: &model.LinearProgram{
+				NbConstraints: 2,
+				NbVariables:   2,
+				VariableNames: []string{"x1", "x2"},
+				Objective:     model.MAXIMIZE,
+				ObjCoeff:      []float64{3, 5},
+				Comparisons:   []model.Comparison{model.LE, model.LE},
+				ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+					{1, 0},
+					{3, 2},
+				}),
+				Rhs:         []float64{4, 18},
+				UpperBounds: []float64{inf, 6},
+			},
+			objCoeff: []model.Range{{Lower: 0, Upper: 7.5}, {Lower: 2, Upper: inf}},
+			rhs:      []model.Range{{Lower: 2, Upper: inf}, {Lower: 12, Upper: 24}},
+		},
+		{
+			name: "MinimizeWithPhaseOne",
+			lp: &model.LinearProgram{
+				NbConstraints: 3,
+				NbVariables:   2,
+				VariableNames: []string{"x", "y"},
+				Objective:     model.MINIMIZE,
+				ObjCoeff:      []float64{2, 3},
+				Comparisons:   []model.Comparison{model.BE, model.LE, model.EQ},
+				ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+					{1, 1},
+					{2, 1},
+					{1, -1},
+				}),
+				Rhs: []float64{10, 20, 2},
+			},
+			objCoeff: []model.Range{{Lower: -3, Upper: inf}, {Lower: -2, Upper: inf}},
+			rhs:      []model.Range{{Lower: 2, Upper: 38.0 / 3}, {Lower: 16, Upper: inf}, {Lower: -10, Upper: 10}},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, opts := range []SolveOptions{{Initialization: TwoPhase}, {Initialization: BigM}, {Algorithm: RevisedSimplex}} {
+			lp := *tt.lp
+			lp.ConstraintCoeff = tt.lp.ConstraintCoeff.Clone()
+			lp.ObjCoeff = append([]float64(nil), tt.lp.ObjCoeff...)
+			lp.Rhs = append([]float64(nil), tt.lp.Rhs...)
+			lp.Comparisons = append([]model.Comparison(nil), tt.lp.Comparisons...)
+
+			result, err := SolveWithOptions(context.Background(), &lp, opts)
+			if err != nil {
+				t.Fatalf("%s: SolveWithOptions() error = %v", tt.name, err)
+			}
+			if result.Sensitivity == nil {
+				t.Fatalf("%s: Expected sensitivity to be computed", tt.name)
+			}
+			if !equalRanges(result.Sensitivity.ObjCoeff, tt.objCoeff, 1e-9) {
+				t.Errorf("%s: Expected objective ranges %v, got %v", tt.name, tt.objCoeff, result.Sensitivity.ObjCoeff)
+			}
+			if !equalRanges(result.Sensitivity.Rhs, tt.rhs, 1e-9) {
+				t.Errorf("%s: Expected RHS ranges %v, got %v", tt.name, tt.rhs, result.Sensitivity.Rhs)
+			}
+		}
+	}
+}
+
+func TestSensitivity_SolutionJSON(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints:   1,
+		NbVariables:     2,
+		VariableNames:   []string{"x1", "x2"},
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        []float64{1, 2},
+		Comparisons:     []model.Comparison{model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}}),
+		Rhs:             []float64{4},
+	}
+	result, err := Solve(lp)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+
+	jsonString, err := result.GetSolutionJSON()
+	if err != nil {
+		t.Fatalf("GetSolutionJSON() error = %v", err)
+	}
+	var solution struct {
+		Sensitivity struct {
+			Objective map[string]struct{ Lower, Upper *float64 }
+			Rhs       []struct{ Lower, Upper *float64 }
+		}
+	}
+	if err := json.Unmarshal([]byte(jsonString), &solution); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	// x2 is basic: its coefficient can drop to 1 and grow without limit
+	x2 := solution.Sensitivity.Objective["x2"]
+	if x2.Lower == nil || *x2.Lower != 1 || x2.Upper != nil {
+		t.Errorf("Expected x2 range [1, null], got %s", jsonString)
+	}
+	if len(solution.Sensitivity.Rhs) != 1 || *solution.Sensitivity.Rhs[0].Lower != 0 || solution.Sensitivity.Rhs[0].Upper != nil {
+		t.Errorf("Expected RHS range [0, null], got %s", jsonString)
+	}
+}
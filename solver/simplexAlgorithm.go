@@ -1,51 +1,35 @@
 package solver
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math"
-	"strings"
+	"time"
 	"github.com/Chemberlein/LinearProgrammingTools/model"
 )
 
 // SimplexTable represents the simplex tableau.
 type SimplexTable struct {
-	data           [][]float64 // (constraints + objective row) x (variables + slacks + RHS)
+	data           [][]float64 // (constraints + objective row) x (variables + slacks + artificials + RHS)
 	basicVariables []float64
-}
-
-// String returns a string representation of the simplex table.
-func (table *SimplexTable) String() string {
-	var builder strings.Builder
-	builder.WriteString("Simplex Tableau:\n")
+	artificials    []int // columns of the artificial variables added for Phase I
 
-	// Get column widths
+	upperBounds       []float64 // upper bound of each column, nil when no variable is bounded
+	atUpper           []bool    // columns substituted by x' = u - x, nonbasic ones sit at their upper bound
+	phaseTwoObjective []float64 // original objective row, kept up to date during Phase I
 
-	colWidths := make([]int, len(table.data[0]))
-	for _, row := range table.data {
-		for j, val := range row {
-			width := len(fmt.Sprintf("%.2f", val))
-			if width > colWidths[j] {
-				colWidths[j] = width
-			}
-		}
-	}
+	pricing PricingRule // nil means Bland's rule
+	limits  *limits     // nil never stops the solve
 
-	// Print header
-	for j := 0; j < len(table.data[0]); j++ {
-		builder.WriteString(fmt.Sprintf("%*s ", colWidths[j], fmt.Sprintf("x%d", j+1)))
-	}
-
-	builder.WriteString("\n")
-
-	// Print data
-	for _, row := range table.data {
-		for j, val := range row {
-			builder.WriteString(fmt.Sprintf("%*.*f ", colWidths[j], 2, val))
-		}
-		builder.WriteString("\n")
-	}
+	names []string // names of the variable and slack columns
+	trace *Trace   // nil records no trace
+	phase string   // phase of the steps recorded in the trace
+}
 
-	return builder.String()
+// String returns a string representation of the simplex table, with the names of the variables and the basis.
+func (table *SimplexTable) String() string {
+	return "Simplex Tableau:\n" + table.Snapshot().String()
 }
 
 // InitializeTableau creates the initial simplex tableau from a standardized linear program.
@@ -54,6 +38,8 @@ func (table *SimplexTable) InitializeTableau(problem *model.LinearProgram) {
 		problem.ToSlackForm()
 	}
 
+	table.names = columnNames(problem)
+
 	m := problem.NbConstraints
 	n := problem.NbVariables // n is now the total number of variables including slacks
 	n_orig := n - m          // number of original variables
@@ -69,9 +55,10 @@ func (table *SimplexTable) InitializeTableau(problem *model.LinearProgram) {
 
 	// Fill constraint rows
 	for i := 0; i < m; i++ {
-		// Copy constraint coefficients
-		for j := 0; j < n; j++ {
-			table.data[i][j] = problem.ConstraintCoeff[i][j]
+		// Copy the non-zero constraint coefficients
+		row := problem.ConstraintCoeff.Rows[i]
+		for k, j := range row.Indices {
+			table.data[i][j] = row.Values[k]
 		}
 
 		// Set RHS
@@ -89,6 +76,38 @@ func (table *SimplexTable) InitializeTableau(problem *model.LinearProgram) {
 
 	// The rest of the objective row is 0
 	table.data[rowIndex][n] = 0.0 // Initial objective value
+
+	// Upper bounds of the variables, lower bounds were shifted to 0 by the canonical form
+	table.upperBounds = make([]float64, n)
+	table.atUpper = make([]bool, n)
+	for j := 0; j < n; j++ {
+		_, table.upperBounds[j] = problem.Bounds(j)
+	}
+}
+
+// upperBound returns the upper bound of the column, +Inf if it has none.
+func (table *SimplexTable) upperBound(col int) float64 {
+	if col >= len(table.upperBounds) {
+		return math.Inf(1)
+	}
+	return table.upperBounds[col]
+}
+
+// complement substitutes x' = u - x for the nonbasic variable of the column,
+// which moves it from its lower to its upper bound or back.
+func (table *SimplexTable) complement(col int) {
+	rhsCol := len(table.data[0]) - 1
+	upper := table.upperBound(col)
+
+	for i := range table.data {
+		table.data[i][rhsCol] -= upper * table.data[i][col]
+		table.data[i][col] *= -1
+	}
+	if table.phaseTwoObjective != nil {
+		table.phaseTwoObjective[rhsCol] -= upper * table.phaseTwoObjective[col]
+		table.phaseTwoObjective[col] *= -1
+	}
+	table.atUpper[col] = !table.atUpper[col]
 }
 
 // FindEnteringVariable finds the entering variable based on Bland's rule.
@@ -99,6 +118,9 @@ func (table *SimplexTable) FindEnteringVariable() int {
 
 	// Search for the first negative coefficient (smallest index)
 	for j := 0; j < len(table.data[objectiveRow])-1; j++ {
+		if table.isArtificial(j) {
+			continue // Artificial variables never re-enter the basis
+		}
 		coefficient := table.data[objectiveRow][j]
 		if coefficient < -epsilon { // Significantly negative
 			return j // Return the first negative coefficient's index
@@ -109,35 +131,48 @@ func (table *SimplexTable) FindEnteringVariable() int {
 }
 
 // FindLeavingVariable finds the leaving variable using the minimum ratio test.
+// A basic variable leaves when it decreases to 0 or increases to its upper bound.
 // When ratios are tied, it selects the one with the smallest row index (Bland's rule).
 func (table *SimplexTable) FindLeavingVariable(pivotCol int) int {
 	numConstraintRows := len(table.data) - 1
-	rhsCol := len(table.data[0]) - 1
 	smallestRatio := math.Inf(1)
 	pivotRow := -1
 	epsilon := 1e-10
 
 	for i := 0; i < numConstraintRows; i++ {
-		pivotColValue := table.data[i][pivotCol]
-		rhsValue := table.data[i][rhsCol]
-
-		if pivotColValue > epsilon {
-			ratio := rhsValue / pivotColValue
-
-			if ratio >= -epsilon { // Non-negative ratio
-				// Use epsilon for comparison to handle ties
-				if ratio < smallestRatio-epsilon {
-					smallestRatio = ratio
-					pivotRow = i
-				}
-				// If tied, pivotRow keeps the smaller index (Bland's rule)
-			}
+		ratio, ok := table.leavingRatio(i, pivotCol)
+		// Use epsilon for comparison to handle ties
+		if ok && ratio < smallestRatio-epsilon {
+			smallestRatio = ratio
+			pivotRow = i
 		}
+		// If tied, pivotRow keeps the smaller index (Bland's rule)
 	}
 
 	return pivotRow
 }
 
+// leavingRatio returns how far the entering column can increase before the basic variable
+// of the row reaches one of its bounds, and false if it never does.
+func (table *SimplexTable) leavingRatio(row, pivotCol int) (float64, bool) {
+	rhsCol := len(table.data[0]) - 1
+	pivotColValue := table.data[row][pivotCol]
+	rhsValue := table.data[row][rhsCol]
+	epsilon := 1e-10
+
+	if pivotColValue > epsilon {
+		ratio := rhsValue / pivotColValue
+		return ratio, ratio >= -epsilon // Non-negative ratio
+	}
+	if pivotColValue < -epsilon {
+		upper := table.upperBound(int(table.basicVariables[row]))
+		if !math.IsInf(upper, 1) {
+			return (upper - rhsValue) / -pivotColValue, true
+		}
+	}
+	return 0, false
+}
+
 // PerformPivot performs the pivot operation on the tableau.
 // It modifies the tableau in-place.
 func (table *SimplexTable) PerformPivot(pivotRow, pivotCol int) {
@@ -193,6 +228,13 @@ func (table *SimplexTable) ExtractSolution(problem *model.LinearProgram) []float
 		}
 	}
 
+	// Undo the substitution x' = u - x of the complemented variables
+	for j := 0; j < numOrigVars; j++ {
+		if table.atUpper[j] {
+			solution[j] = table.upperBound(j) - solution[j]
+		}
+	}
+
 	// The last element of the solution is the objective value
 	objectiveRow := len(table.data) - 1
 	objectiveValue := table.data[objectiveRow][rhsCol]
@@ -202,38 +244,397 @@ func (table *SimplexTable) ExtractSolution(problem *model.LinearProgram) []float
 	return solution
 }
 
-// Solve will find the values for the variables.
-func Solve(lp *model.LinearProgram) error {
-	originalObjective := lp.Objective
-	lp.ToSlackForm()
+// ExtractDuals returns the dual value of each row of the slack form for the maximized objective,
+// which the objective row holds under the slack columns.
+func (table *SimplexTable) ExtractDuals(problem *model.LinearProgram) []float64 {
+	objectiveRow := len(table.data) - 1
+	firstSlack := problem.NbVariables - problem.NbConstraints
 
-	var table SimplexTable
-	table.InitializeTableau(lp)
+	duals := make([]float64, problem.NbConstraints)
+	for i := range duals {
+		duals[i] = table.data[objectiveRow][firstSlack+i]
+	}
+	return duals
+}
 
-	if !table.IsInitiallyFeasible() {
-		return fmt.Errorf("infeasible problem")
+// firstSlack returns the column of the slack variable of the first row.
+// The structural and slack columns come before the artificial ones.
+func (table *SimplexTable) firstSlack() int {
+	return len(table.data[0]) - 1 - len(table.artificials) - (len(table.data) - 1)
+}
+
+// rowMultipliers returns the entries of the row under the slack columns, which are the multipliers
+// that combine the rows of the slack form into it.
+func (table *SimplexTable) rowMultipliers(row int) []float64 {
+	first := table.firstSlack()
+	multipliers := make([]float64, len(table.data)-1)
+	for i := range multipliers {
+		multipliers[i] = table.data[row][first+i]
+	}
+	return multipliers
+}
+
+// unboundedError returns the error for an entering column without a leaving row, with the ray over the
+// structural and slack columns along which the objective improves without limit.
+func (table *SimplexTable) unboundedError(pivotCol int) error {
+	return &UnboundedError{Ray: table.edgeDirection(pivotCol)}
+}
+
+// edgeDirection returns the change of the structural and slack columns per unit increase of the nonbasic
+// column, which moves the basic variables along the edge of the feasible region leaving the current vertex.
+func (table *SimplexTable) edgeDirection(pivotCol int) []float64 {
+	ray := make([]float64, table.firstSlack()+len(table.basicVariables))
+	ray[pivotCol] = 1
+	for i, basic := range table.basicVariables {
+		if col := int(basic); col < len(ray) {
+			ray[col] = -table.data[i][pivotCol]
+		}
+	}
+
+	// A complemented column holds x' = u - x, which moves the other way
+	for j := range ray {
+		if j < len(table.atUpper) && table.atUpper[j] {
+			ray[j] *= -1
+		}
 	}
+	return ray
+}
+
+// optimize runs simplex iterations from a feasible basis until the objective row is optimal.
+// After DegeneratePivotLimit degenerate pivots in a row, Bland's rule replaces the pricing rule
+// until the objective improves again.
+func (table *SimplexTable) optimize() error {
+	epsilon := 1e-10
+	degeneratePivots := 0
 
 	for {
-		pivotCol := table.FindEnteringVariable()
+		bland := degeneratePivots >= DegeneratePivotLimit
+		pivotCol := table.selectEntering(bland)
 		if pivotCol == -1 {
-			lp.ObjVar = table.ExtractSolution(lp)
-			if originalObjective == model.MINIMIZE {
-				lp.ObjVar[len(lp.ObjVar)-1] *= -1
-			}
-			lp.State = model.Undefined
 			return nil
 		}
+		if err := table.limits.step(); err != nil {
+			return err
+		}
 
 		pivotRow := table.FindLeavingVariable(pivotCol)
+		step := math.Inf(1)
+		if pivotRow != -1 {
+			step, _ = table.leavingRatio(pivotRow, pivotCol)
+		}
+
+		// The entering variable reaches its own upper bound first: flip it without a pivot
+		upper := table.upperBound(pivotCol)
+		boundFlip := !math.IsInf(upper, 1) && upper <= step
+		if table.trace != nil {
+			table.record(table.primalStep(pivotRow, pivotCol, boundFlip, bland))
+		}
+		if boundFlip {
+			if upper > epsilon {
+				degeneratePivots = 0
+			}
+			table.complement(pivotCol)
+			continue
+		}
+
 		if pivotRow == -1 {
-			return fmt.Errorf("Unbounded")
+			return table.unboundedError(pivotCol)
 		}
 
-		table.PerformPivot(pivotRow, pivotCol)
+		if step > epsilon {
+			degeneratePivots = 0
+		} else {
+			degeneratePivots++
+		}
+		if table.pricing != nil {
+			table.pricing.Pivoting(table, pivotRow, pivotCol)
+		}
+		table.pivot(pivotRow, pivotCol)
+	}
+}
 
-		table.basicVariables[pivotRow] = float64(pivotCol)
+// selectEntering asks the pricing rule for the entering variable, or uses Bland's rule.
+func (table *SimplexTable) selectEntering(bland bool) int {
+	if table.pricing == nil || bland {
+		return table.FindEnteringVariable()
 	}
+	return table.pricing.SelectEntering(table)
+}
+
+// pivot makes pivotCol basic in pivotRow. A leaving variable that reached its upper bound
+// is complemented, so that it sits at 0 like every other nonbasic variable.
+func (table *SimplexTable) pivot(pivotRow, pivotCol int) {
+	leaving := int(table.basicVariables[pivotRow])
+	leavesAtUpper := table.data[pivotRow][pivotCol] < 0 && !table.isArtificial(leaving)
+
+	table.PerformPivot(pivotRow, pivotCol)
 
+	table.basicVariables[pivotRow] = float64(pivotCol)
+
+	if leavesAtUpper {
+		table.complement(leaving)
+	}
+}
+
+// Solve will find the values for the variables.
+// Problems whose slack basis is infeasible are first solved for a feasible basis with Phase I.
+func Solve(lp *model.LinearProgram) (*Result, error) {
+	return SolveWithOptions(context.Background(), lp, SolveOptions{})
+}
+
+// SolveWithOptions will find the values for the variables using the given options. The problem is left
+// unchanged: the solve works on a copy and returns its outcome as a Result. An infeasible or unbounded problem,
+// or a solve stopped by a limit, returns both the Result with its Status and the error, which matches
+// ErrInfeasible, ErrUnbounded, ErrIterationLimit, ErrTimeLimit or ErrCanceled with errors.Is. The solve stops
+// with a *LimitError when the context is done, or at the time or iteration limit of the options. Any other error,
+// such as invalid bounds or a Trace requested from the revised simplex or the interior-point method, returns a nil
+// Result.
+func SolveWithOptions(ctx context.Context, lp *model.LinearProgram, opts SolveOptions) (*Result, error) {
+	start := time.Now()
+	lim := newLimits(ctx, opts)
+	if opts.Trace {
+		opts.trace = &Trace{}
+	}
+	solved := lp.Clone()
+	err := solve(solved, opts, lim)
+	status, ok := statusOf(err)
+	if !ok {
+		return nil, err
+	}
+
+	result := &Result{Status: status}
+	var limitErr *LimitError
+	if status == Optimal {
+		result = optimalResult(lp, solved)
+	} else if errors.As(err, &limitErr) {
+		result.Basis = limitErr.Basis
+	}
+	result.Iterations, result.Duration = lim.iterations, time.Since(start)
+	if opts.trace != nil {
+		opts.trace.Summary = opts.trace.summarize(lp, result)
+		result.Trace = opts.trace
+	}
+	return result, err
+}
+
+// solve finds the values for the variables in place: the problem is converted to slack form and receives ObjVar
+// and the other solution fields. The iterations are counted and stopped by the limits.
+func solve(lp *model.LinearProgram, opts SolveOptions, lim *limits) error {
+	err := checkBounds(lp)
+	if err != nil {
+		return err
+	}
+
+	originalObjective := lp.Objective
+	lp.Sensitivity = nil
+	lp.Duals, lp.Slacks, lp.ReducedCosts = nil, nil, nil
+	lp.Basis = nil
+	lp.AlternativeOptima = false
+	if opts.Presolve {
+		return solvePresolved(lp, opts, lim)
+	}
+	if opts.Scaling != NoScaling {
+		return solveScaled(lp, opts, lim)
+	}
+	lp.ToSlackForm()
+
+	var solution []float64
+	switch opts.algorithmFor(lp) {
+	case RevisedSimplex:
+		solution, err = solveRevised(lp, opts, lim)
+	case InteriorPoint:
+		solution, err = solveInteriorPoint(lp, opts, lim)
+	default:
+		solution, err = solveTableau(lp, opts, lim)
+	}
+	if err != nil {
+		return originalCertificate(lp, err)
+	}
+
+	lp.ObjVar = originalSolution(lp, solution, originalObjective)
 	return nil
 }
+
+// originalSolution maps a solution of the slack form, followed by its maximized objective value, back to the
+// original variables and objective.
+func originalSolution(lp *model.LinearProgram, solution []float64, objective model.Objectiv) []float64 {
+	objectiveValue := solution[len(solution)-1] + lp.ObjConstant
+	if objective == model.MINIMIZE {
+		objectiveValue *= -1
+	}
+	return append(lp.OriginalValues(solution[:len(solution)-1]), objectiveValue)
+}
+
+// solveTableau solves the problem on a dense simplex tableau with the primal or dual simplex.
+func solveTableau(lp *model.LinearProgram, opts SolveOptions, lim *limits) ([]float64, error) {
+	table, err := solvedTableau(lp, opts, lim)
+	if err != nil {
+		return nil, err
+	}
+
+	solution := table.ExtractSolution(lp)
+	lp.SetDuals(table.ExtractDuals(lp), solution[:len(solution)-1])
+	lp.Sensitivity = table.Sensitivity(lp)
+	lp.Basis = table.Basis()
+	lp.AlternativeOptima = table.alternativeOptimum(lp)
+	return solution, nil
+}
+
+// solvedTableau runs the primal or dual tableau simplex on the slack form and returns the optimal tableau.
+// A solve stopped by a limit returns the *LimitError with the basis reached.
+func solvedTableau(lp *model.LinearProgram, opts SolveOptions, lim *limits) (*SimplexTable, error) {
+	var table SimplexTable
+	table.InitializeTableau(lp)
+	if opts.WarmStart != nil && !table.WarmStart(opts.WarmStart) {
+		table.InitializeTableau(lp) // The basis does not fit, start from the slack basis
+	}
+	table.pricing = NewPricingRule(opts.Pricing)
+	table.pricing.Reset(&table)
+	table.limits = lim
+	table.trace = opts.trace
+
+	var err error
+	switch opts.Algorithm {
+	case DualSimplex:
+		err = table.solveDual()
+	default:
+		err = table.solvePrimal(opts)
+	}
+	if table.trace != nil {
+		table.trace.Final = table.Snapshot()
+	}
+	var limitErr *LimitError
+	if errors.As(err, &limitErr) {
+		limitErr.Basis = table.Basis()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+// optimalTableau converts the problem to slack form and returns its optimal tableau. The algorithm is always a
+// tableau simplex, the primal one unless the dual simplex is requested, for the callers that work on the tableau.
+func optimalTableau(lp *model.LinearProgram, opts SolveOptions) (*SimplexTable, error) {
+	if err := checkBounds(lp); err != nil {
+		return nil, err
+	}
+	lp.ToSlackForm()
+
+	if opts.Algorithm != DualSimplex {
+		opts.Algorithm = PrimalSimplex
+	}
+	table, err := solvedTableau(lp, opts, newLimits(context.Background(), opts))
+	if err != nil {
+		return nil, originalCertificate(lp, err)
+	}
+	table.pricing, table.limits, table.trace = nil, nil, nil
+	return table, nil
+}
+
+// solveRevised solves the problem with the revised simplex, which only supports the two-phase initialization.
+func solveRevised(lp *model.LinearProgram, opts SolveOptions, lim *limits) ([]float64, error) {
+	if opts.Initialization != TwoPhase {
+		return nil, fmt.Errorf("the revised simplex only supports the two-phase initialization")
+	}
+	if opts.trace != nil {
+		return nil, fmt.Errorf("the revised simplex does not record a trace")
+	}
+
+	var rs RevisedSimplexSolver
+	err := rs.Initialize(lp)
+	if err == nil && opts.WarmStart != nil && !rs.WarmStart(opts.WarmStart) {
+		err = rs.Initialize(lp) // The basis does not fit, start from the slack basis
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rs.limits = lim
+	rs.pricing = opts.Pricing
+	err = rs.Solve()
+	var limitErr *LimitError
+	if errors.As(err, &limitErr) {
+		limitErr.Basis = rs.Basis()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	solution := rs.ExtractSolution(lp)
+	lp.SetDuals(rs.ExtractDuals(), solution[:len(solution)-1])
+	lp.Sensitivity = rs.Sensitivity(lp)
+	lp.Basis = rs.Basis()
+	lp.AlternativeOptima = rs.alternativeOptimum(lp)
+	return solution, nil
+}
+
+// solveInteriorPoint solves the problem with the primal-dual interior-point method. The infeasible or unbounded
+// problems it suspects, and those on which it does not converge, are solved again with the simplex algorithm that
+// Automatic picks for the problem, which confirms the status with a certificate.
+func solveInteriorPoint(lp *model.LinearProgram, opts SolveOptions, lim *limits) ([]float64, error) {
+	if opts.trace != nil {
+		return nil, fmt.Errorf("the interior-point method does not record a trace")
+	}
+
+	var ipm InteriorPointSolver
+	ipm.Initialize(lp)
+	ipm.limits = lim
+
+	err := ipm.Solve(opts.InteriorPointLog)
+	if errors.Is(err, ErrInfeasible) || errors.Is(err, ErrUnbounded) || errors.Is(err, errNotConverged) {
+		opts.Algorithm = Automatic
+		if opts.algorithmFor(lp) == RevisedSimplex {
+			return solveRevised(lp, opts, lim)
+		}
+		return solveTableau(lp, opts, lim)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	solution := ipm.ExtractSolution(lp)
+	lp.SetDuals(ipm.ExtractDuals(lp), solution[:len(solution)-1])
+	return solution, nil
+}
+
+// solvePrimal finds a feasible basis with the configured initialization and runs the primal simplex.
+// A warm start basis that is still dual feasible, for example after a change of the RHS, is made
+// feasible with the dual simplex instead.
+func (table *SimplexTable) solvePrimal(opts SolveOptions) error {
+	if opts.WarmStart != nil && !table.IsInitiallyFeasible() && table.IsDualFeasible() {
+		err := table.DualSimplex()
+		if err != nil {
+			return err
+		}
+	}
+
+	table.phase = TracePhaseTwo
+	if !table.IsInitiallyFeasible() {
+		switch opts.Initialization {
+		case BigM:
+			table.AddBigMPenalties(opts.bigMPenalty())
+			table.phase = TraceBigM
+		default:
+			err := table.PhaseOne()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err := table.optimizeBigM()
+	if err != nil {
+		return err
+	}
+
+	return table.CheckArtificialVariables()
+}
+
+// solveDual runs the dual simplex, which needs the slack basis to be dual feasible.
+func (table *SimplexTable) solveDual() error {
+	if !table.IsDualFeasible() {
+		return fmt.Errorf("dual simplex requires a dual feasible basis")
+	}
+	return table.DualSimplex()
+}
@@ -1,7 +1,10 @@
 package solver
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"math"
 	"testing"
 
 	"github.com/Chemberlein/LinearProgrammingTools/model"
@@ -15,23 +18,23 @@ func TestSolve(t *testing.T) {
 		Objective:     model.MAXIMIZE,
 		ObjCoeff:      []float64{3, 5},
 		Comparisons:   []model.Comparison{model.LE, model.LE, model.LE},
-		ConstraintCoeff: [][]float64{
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
 			{1, 0},
 			{0, 2},
 			{3, 2},
-		},
+		}),
 		Rhs: []float64{4, 12, 18},
 	}
 
-	err := Solve(lp)
+	result, err := Solve(lp)
 	if err != nil {
 		t.Fatalf("Solve() error = %v", err)
 	}
 
 	// Expected solution for this problem is x1=2, x2=6, objective=36
 	expectedSolution := []float64{2, 6, 36}
-	if !equalFloat64Slices(lp.ObjVar, expectedSolution, 1e-9) {
-		t.Errorf("Expected solution to be %v, got %v", expectedSolution, lp.ObjVar)
+	if !equalFloat64Slices(result.ObjVar, expectedSolution, 1e-9) {
+		t.Errorf("Expected solution to be %v, got %v", expectedSolution, result.ObjVar)
 	}
 }
 
@@ -43,25 +46,25 @@ func TestGetSolutionJSON(t *testing.T) {
 		Objective:     model.MAXIMIZE,
 		ObjCoeff:      []float64{3, 5},
 		Comparisons:   []model.Comparison{model.LE, model.LE, model.LE},
-		ConstraintCoeff: [][]float64{
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
 			{1, 0},
 			{0, 2},
 			{3, 2},
-		},
+		}),
 		Rhs: []float64{4, 12, 18},
 	}
 
-	err := Solve(lp)
+	result, err := Solve(lp)
 	if err != nil {
 		t.Fatalf("Solve() error = %v", err)
 	}
 
-	jsonString, err := lp.GetSolutionJSON()
+	jsonString, err := result.GetSolutionJSON()
 	if err != nil {
 		t.Fatalf("GetSolutionJSON() error = %v", err)
 	}
 
-	var solution map[string]float64
+	var solution map[string]interface{}
 	err = json.Unmarshal([]byte(jsonString), &solution)
 	if err != nil {
 		t.Fatalf("Failed to unmarshal JSON: %v", err)
@@ -93,16 +96,16 @@ func TestSolve_Unbounded(t *testing.T) {
 		Objective:     model.MAXIMIZE,
 		ObjCoeff:      []float64{1, 1},
 		Comparisons:   []model.Comparison{model.LE, model.LE},
-		ConstraintCoeff: [][]float64{
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
 			{1, -1},
 			{-1, 1},
-		},
+		}),
 		Rhs: []float64{1, 1},
 	}
 
-	err := Solve(lp)
-	if err == nil || err.Error() != "Unbounded" {
-		t.Errorf("Expected error to be 'Unbounded', got %v", err)
+	_, err := Solve(lp)
+	if err == nil || err.Error() != "unbounded problem" {
+		t.Errorf("Expected error to be 'unbounded problem', got %v", err)
 	}
 }
 
@@ -114,14 +117,14 @@ func TestSolve_Infeasible(t *testing.T) {
 		Objective:     model.MAXIMIZE,
 		ObjCoeff:      []float64{1, 1},
 		Comparisons:   []model.Comparison{model.LE, model.BE},
-		ConstraintCoeff: [][]float64{
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
 			{1, 1},
 			{1, 1},
-		},
+		}),
 		Rhs: []float64{1, 2},
 	}
 
-	err := Solve(lp)
+	_, err := Solve(lp)
 	if err == nil {
 		t.Errorf("Expected error for infeasible problem, got nil")
 	} else {
@@ -156,3 +159,152 @@ func TestIsInitiallyFeasible(t *testing.T) {
 		}
 	})
 }
+
+func TestSolve_Bounds(t *testing.T) {
+	build := func() *model.LinearProgram {
+		return &model.LinearProgram{
+			NbConstraints: 2,
+			NbVariables:   3,
+			VariableNames: []string{"x", "y", "z"},
+			Objective:     model.MAXIMIZE,
+			ObjCoeff:      []float64{2, 3, 1},
+			Comparisons:   []model.Comparison{model.LE, model.BE},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+				{1, 1, 1},
+				{1, -1, 0},
+			}),
+			Rhs:         []float64{20, -4},
+			LowerBounds: []float64{0, 1, 2},
+			UpperBounds: []float64{3, 6, math.Inf(1)},
+		}
+	}
+
+	// Expected solution for this problem is x=3, y=6, z=11, objective=35
+	expectedSolution := []float64{3, 6, 11, 35}
+	for name, opts := range map[string]SolveOptions{
+		"PrimalSimplex":  {Algorithm: PrimalSimplex},
+		"BigM":           {Algorithm: PrimalSimplex, Initialization: BigM},
+		"RevisedSimplex": {Algorithm: RevisedSimplex},
+		"InteriorPoint":  {Algorithm: InteriorPoint},
+	} {
+		t.Run(name, func(t *testing.T) {
+			lp := build()
+			result, err := SolveWithOptions(context.Background(), lp, opts)
+			if err != nil {
+				t.Fatalf("SolveWithOptions() error = %v", err)
+			}
+			if !equalFloat64Slices(result.ObjVar, expectedSolution, 1e-6) {
+				t.Errorf("Expected solution to be %v, got %v", expectedSolution, result.ObjVar)
+			}
+		})
+	}
+}
+
+func TestSolve_BoundsDualSimplex(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints:   1,
+		NbVariables:     2,
+		VariableNames:   []string{"x", "y"},
+		Objective:       model.MINIMIZE,
+		ObjCoeff:        []float64{1, 2},
+		Comparisons:     []model.Comparison{model.BE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}}),
+		Rhs:             []float64{5},
+		UpperBounds:     []float64{3, 10},
+	}
+
+	result, err := SolveWithOptions(context.Background(), lp, SolveOptions{Algorithm: DualSimplex})
+	if err != nil {
+		t.Fatalf("SolveWithOptions() error = %v", err)
+	}
+
+	// Expected solution for this problem is x=3, y=2, objective=7
+	expectedSolution := []float64{3, 2, 7}
+	if !equalFloat64Slices(result.ObjVar, expectedSolution, 1e-9) {
+		t.Errorf("Expected solution to be %v, got %v", expectedSolution, result.ObjVar)
+	}
+}
+
+func TestSolve_InvalidBounds(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints:   1,
+		NbVariables:     1,
+		VariableNames:   []string{"x"},
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        []float64{1},
+		Comparisons:     []model.Comparison{model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1}}),
+		Rhs:             []float64{10},
+		LowerBounds:     []float64{5},
+		UpperBounds:     []float64{4},
+	}
+
+	result, err := Solve(lp)
+	if !errors.Is(err, ErrInfeasible) {
+		t.Fatalf("Expected an error matching %v, got %v", ErrInfeasible, err)
+	}
+	if result == nil || result.Status != Infeasible {
+		t.Errorf("Expected the status %v, got %+v", Infeasible, result)
+	}
+
+	lp.LowerBounds = []float64{math.NaN()}
+	_, err = Solve(lp)
+	if err == nil || err.Error() != "invalid bounds for variable x" {
+		t.Errorf("Expected the error 'invalid bounds for variable x', got %v", err)
+	}
+}
+
+func TestSolve_FreeAndNonPositiveVariables(t *testing.T) {
+	build := func() *model.LinearProgram {
+		return &model.LinearProgram{
+			NbConstraints: 3,
+			NbVariables:   3,
+			VariableNames: []string{"x", "y", "z"},
+			Objective:     model.MINIMIZE,
+			ObjCoeff:      []float64{1, 1, 1},
+			Comparisons:   []model.Comparison{model.EQ, model.BE, model.BE},
+			ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+				{1, -1, 0},
+				{1, 3, 0},
+				{0, 0, 1},
+			}),
+			Rhs:   []float64{-4, -2, -7},
+			Signs: []model.Sign{model.Free, model.Free, model.NonPositive},
+		}
+	}
+
+	// Expected solution for this problem is x=-3.5, y=0.5, z=-7, objective=-10
+	expectedSolution := []float64{-3.5, 0.5, -7, -10}
+	for name, opts := range map[string]SolveOptions{
+		"PrimalSimplex":  {Algorithm: PrimalSimplex},
+		"BigM":           {Algorithm: PrimalSimplex, Initialization: BigM},
+		"RevisedSimplex": {Algorithm: RevisedSimplex},
+		"InteriorPoint":  {Algorithm: InteriorPoint},
+	} {
+		t.Run(name, func(t *testing.T) {
+			lp := build()
+			result, err := SolveWithOptions(context.Background(), lp, opts)
+			if err != nil {
+				t.Fatalf("SolveWithOptions() error = %v", err)
+			}
+			if !equalFloat64Slices(result.ObjVar, expectedSolution, 1e-6) {
+				t.Errorf("Expected solution to be %v, got %v", expectedSolution, result.ObjVar)
+			}
+
+			jsonString, err := result.GetSolutionJSON()
+			if err != nil {
+				t.Fatalf("GetSolutionJSON() error = %v", err)
+			}
+			var solution map[string]interface{}
+			if err := json.Unmarshal([]byte(jsonString), &solution); err != nil {
+				t.Fatalf("Failed to unmarshal JSON: %v", err)
+			}
+			for _, key := range []string{"duals", "slacks", "reducedCosts", "sensitivity", "uniqueOptimum"} {
+				delete(solution, key)
+			}
+			if x, _ := solution["x"].(float64); len(solution) != 4 || math.Abs(x+3.5) > 1e-6 {
+				t.Errorf("Expected the solution JSON to hold the original variables, got %v", solution)
+			}
+		})
+	}
+}
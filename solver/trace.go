@@ -0,0 +1,306 @@
+package solver
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// The phases of a TraceStep.
+const (
+	TracePhaseOne    = "Phase I"      // Minimizing the artificial variables
+	TracePhaseTwo    = "Phase II"     // Optimizing the objective from a feasible basis
+	TraceBigM        = "Big-M"        // Optimizing the objective penalized by the artificial variables
+	TraceDualSimplex = "Dual simplex" // Restoring feasibility from a dual feasible basis
+)
+
+// Trace records the iterations of the tableau simplex, see SolveOptions.Trace. The tableaus are those of the
+// problem as solved: in slack form, after presolve and scaling if they are enabled.
+type Trace struct {
+	Steps   []TraceStep      `json:"steps"`
+	Final   *TableauSnapshot `json:"final"`             // Tableau when the solve ended
+	Summary string           `json:"summary,omitempty"` // Why the solve ended with the status of the result
+}
+
+// TraceStep is one iteration of the tableau simplex. In the primal simplex, the entering variable is chosen from
+// the objective row and the ratio test picks the leaving one; the dual simplex picks the leaving variable first.
+type TraceStep struct {
+	Iteration int    `json:"iteration"`
+	Phase     string `json:"phase"`
+	Entering  string `json:"entering,omitempty"` // Empty when the dual ratio test finds no candidate
+	Leaving   string `json:"leaving,omitempty"`  // Empty for a bound flip or when no variable limits the entering one
+	// Row and column of the pivot in Tableau, -1 without a pivot
+	PivotRow     int     `json:"pivotRow"`
+	PivotColumn  int     `json:"pivotColumn"`
+	PivotElement float64 `json:"pivotElement"`
+	ReducedCost  float64 `json:"reducedCost"`         // Objective row entry of the entering column
+	BoundFlip    bool    `json:"boundFlip,omitempty"` // The entering variable moves to its other bound, without a pivot
+	Rule         string  `json:"rule,omitempty"`      // Pricing that chose the entering variable of the primal simplex
+	// Candidates of the ratio test: the basic variables that limit the entering one in the primal simplex, the
+	// columns that can enter in the dual simplex
+	Ratios      []TraceRatio     `json:"ratios,omitempty"`
+	Explanation string           `json:"explanation"` // The choices of the step in plain English
+	Tableau     *TableauSnapshot `json:"tableau"`     // Tableau before the step
+}
+
+// TraceRatio is a candidate of a ratio test, Ratio = Numerator / Denominator. In the primal simplex, Variable is a
+// basic variable, the numerator is its distance to the bound it moves to and the denominator the rate at which it
+// moves. In the dual simplex, Variable is a nonbasic variable, the numerator its reduced cost and the denominator
+// the magnitude of its entry in the pivot row.
+type TraceRatio struct {
+	Variable    string  `json:"variable"`
+	Numerator   float64 `json:"numerator"`
+	Denominator float64 `json:"denominator"`
+	Ratio       float64 `json:"ratio"`
+}
+
+// TableauSnapshot is a copy of a simplex tableau labelled with the names of its columns and of the basic
+// variables. A column holding a variable substituted by x' = u - x, which sits at its upper bound when nonbasic, is
+// named with a trailing prime.
+type TableauSnapshot struct {
+	Columns []string    `json:"columns"` // Names of the columns, then "RHS"
+	Basis   []string    `json:"basis"`   // Basic variable of each constraint row
+	Rows    [][]float64 `json:"rows"`    // Constraint rows, then the objective row
+}
+
+// GetJSON returns the trace in JSON format.
+func (trace *Trace) GetJSON() (string, error) {
+	jsonBytes, err := json.Marshal(trace)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}
+
+// String returns the trace as text: the tableau of every step, followed by the variables and ratios chosen and
+// their explanation, then the final tableau and the summary.
+func (trace *Trace) String() string {
+	var builder strings.Builder
+	for _, step := range trace.Steps {
+		builder.WriteString(fmt.Sprintf("Iteration %d (%s)\n", step.Iteration, step.Phase))
+		builder.WriteString(step.Tableau.String())
+		builder.WriteString(step.describe())
+		builder.WriteString(step.Explanation + "\n\n")
+	}
+	if trace.Final != nil {
+		builder.WriteString("Final tableau\n")
+		builder.WriteString(trace.Final.String())
+	}
+	if trace.Summary != "" {
+		builder.WriteString(trace.Summary + "\n")
+	}
+	return builder.String()
+}
+
+// describe returns the choices of the step as text.
+func (step TraceStep) describe() string {
+	var builder strings.Builder
+	if step.Entering != "" {
+		builder.WriteString(fmt.Sprintf("Entering: %s (reduced cost %.2f)\n", step.Entering, step.ReducedCost))
+	}
+	if len(step.Ratios) > 0 {
+		ratios := make([]string, len(step.Ratios))
+		for k, r := range step.Ratios {
+			ratios[k] = fmt.Sprintf("%s %.2f / %.2f = %.2f", r.Variable, r.Numerator, r.Denominator, r.Ratio)
+		}
+		builder.WriteString("Ratios: " + strings.Join(ratios, ", ") + "\n")
+	}
+	switch {
+	case step.BoundFlip:
+		builder.WriteString(fmt.Sprintf("Bound flip: %s moves to its other bound\n", step.Entering))
+	case step.Leaving != "" && step.PivotColumn != -1:
+		builder.WriteString(fmt.Sprintf("Leaving: %s (pivot element %.2f)\n", step.Leaving, step.PivotElement))
+	case step.Leaving != "":
+		builder.WriteString(fmt.Sprintf("Leaving: %s, no variable can enter\n", step.Leaving))
+	default:
+		builder.WriteString("No variable leaves\n")
+	}
+	return builder.String()
+}
+
+// String returns the tableau as text, with the basic variable of every row in front of it and z in front of the
+// objective row.
+func (snapshot *TableauSnapshot) String() string {
+	labels := append(append([]string{"Basis"}, snapshot.Basis...), "z")
+	labelWidth := 0
+	for _, label := range labels {
+		labelWidth = max(labelWidth, len(label))
+	}
+
+	colWidths := make([]int, len(snapshot.Columns))
+	for j, name := range snapshot.Columns {
+		colWidths[j] = len(name)
+	}
+	for _, row := range snapshot.Rows {
+		for j, val := range row {
+			colWidths[j] = max(colWidths[j], len(fmt.Sprintf("%.2f", val)))
+		}
+	}
+
+	var builder strings.Builder
+	cells := []string{fmt.Sprintf("%-*s", labelWidth, labels[0])}
+	for j, name := range snapshot.Columns {
+		cells = append(cells, fmt.Sprintf("%*s", colWidths[j], name))
+	}
+	builder.WriteString(strings.Join(cells, " ") + "\n")
+	for i, row := range snapshot.Rows {
+		cells = []string{fmt.Sprintf("%-*s", labelWidth, labels[i+1])}
+		for j, val := range row {
+			cells = append(cells, fmt.Sprintf("%*.*f", colWidths[j], 2, val))
+		}
+		builder.WriteString(strings.Join(cells, " ") + "\n")
+	}
+	return builder.String()
+}
+
+// Snapshot returns a copy of the tableau labelled with the names of the variables. A tableau built without a
+// problem names its columns x1, x2, ...
+func (table *SimplexTable) Snapshot() *TableauSnapshot {
+	rhsCol := len(table.data[0]) - 1
+	snapshot := &TableauSnapshot{
+		Columns: make([]string, rhsCol+1),
+		Basis:   make([]string, len(table.basicVariables)),
+		Rows:    make([][]float64, len(table.data)),
+	}
+	for j := 0; j < rhsCol; j++ {
+		snapshot.Columns[j] = table.columnName(j)
+		if j < len(table.atUpper) && table.atUpper[j] {
+			snapshot.Columns[j] += "'"
+		}
+	}
+	snapshot.Columns[rhsCol] = "RHS"
+	for i, basic := range table.basicVariables {
+		snapshot.Basis[i] = table.columnName(int(basic))
+	}
+	for i, row := range table.data {
+		snapshot.Rows[i] = make([]float64, len(row))
+		for j, value := range row {
+			if value != 0 { // -0 is written as 0
+				snapshot.Rows[i][j] = value
+			}
+		}
+	}
+	return snapshot
+}
+
+// columnName returns the name of the variable of the column: the name given by the problem, a1, a2, ... for the
+// artificial variables and x1, x2, ... otherwise.
+func (table *SimplexTable) columnName(col int) string {
+	if col < len(table.names) {
+		return table.names[col]
+	}
+	for k, artificial := range table.artificials {
+		if artificial == col {
+			return fmt.Sprintf("a%d", k+1)
+		}
+	}
+	return fmt.Sprintf("x%d", col+1)
+}
+
+// columnNames returns the names of the columns of the slack form: the original variables, the negative parts of
+// the split variables, then the slack variables.
+func columnNames(lp *model.LinearProgram) []string {
+	numOrigVars := lp.NbVariables - len(lp.SplitVariables) - len(lp.SlackVariablesNames)
+	names := make([]string, 0, lp.NbVariables)
+	for j := 0; j < numOrigVars; j++ {
+		names = append(names, lp.VariableName(j))
+	}
+	names = append(names, lp.SplitVariablesNames...)
+	return append(names, lp.SlackVariablesNames...)
+}
+
+// record appends the step to the trace, with the phase and a snapshot of the tableau before the step.
+func (table *SimplexTable) record(step TraceStep) {
+	step.Iteration = len(table.trace.Steps) + 1
+	step.Phase = table.phase
+	step.Tableau = table.Snapshot()
+	table.trace.Steps = append(table.trace.Steps, step)
+}
+
+// primalStep returns the step of the primal simplex with the entering column, which leaves the basis in pivotRow
+// (-1 if none does) or moves to its other bound. bland tells that Bland's rule chose the column.
+func (table *SimplexTable) primalStep(pivotRow, pivotCol int, boundFlip, bland bool) TraceStep {
+	rhsCol := len(table.data[0]) - 1
+	pricing := pricingOf(table.pricing)
+	if bland {
+		pricing = BlandPricing
+	}
+	step := TraceStep{
+		Entering:    table.columnName(pivotCol),
+		PivotRow:    -1,
+		PivotColumn: -1,
+		ReducedCost: table.data[len(table.data)-1][pivotCol],
+		BoundFlip:   boundFlip,
+		Rule:        pricing.String(),
+	}
+	for i := 0; i < len(table.data)-1; i++ {
+		ratio, ok := table.leavingRatio(i, pivotCol)
+		if !ok {
+			continue
+		}
+		numerator, denominator := table.data[i][rhsCol], table.data[i][pivotCol]
+		if denominator < 0 {
+			numerator, denominator = table.upperBound(int(table.basicVariables[i]))-numerator, -denominator
+		}
+		step.Ratios = append(step.Ratios, TraceRatio{
+			Variable:    table.columnName(int(table.basicVariables[i])),
+			Numerator:   numerator,
+			Denominator: denominator,
+			Ratio:       ratio,
+		})
+	}
+	if pivotRow != -1 && !boundFlip {
+		step.Leaving = table.columnName(int(table.basicVariables[pivotRow]))
+		step.PivotRow, step.PivotColumn = pivotRow, pivotCol
+		step.PivotElement = table.data[pivotRow][pivotCol]
+	}
+	step.Explanation = step.explainPrimal(table.upperBound(pivotCol), bland && pricingOf(table.pricing) != BlandPricing)
+	return step
+}
+
+// dualStep returns the step of the dual simplex with the leaving row, which the entering column replaces in the
+// basis (-1 if no column can).
+func (table *SimplexTable) dualStep(pivotRow, pivotCol int) TraceStep {
+	objectiveRow := len(table.data) - 1
+	epsilon := 1e-10
+	step := TraceStep{
+		Leaving:     table.columnName(int(table.basicVariables[pivotRow])),
+		PivotRow:    -1,
+		PivotColumn: -1,
+	}
+	for j := 0; j < len(table.data[pivotRow])-1; j++ {
+		if value := table.data[pivotRow][j]; value < -epsilon && !table.isArtificial(j) {
+			step.Ratios = append(step.Ratios, TraceRatio{
+				Variable:    table.columnName(j),
+				Numerator:   table.data[objectiveRow][j],
+				Denominator: -value,
+				Ratio:       math.Abs(table.data[objectiveRow][j] / value),
+			})
+		}
+	}
+	if pivotCol != -1 {
+		step.Entering = table.columnName(pivotCol)
+		step.PivotRow, step.PivotColumn = pivotRow, pivotCol
+		step.PivotElement = table.data[pivotRow][pivotCol]
+		step.ReducedCost = table.data[objectiveRow][pivotCol]
+	}
+	step.Explanation = step.explainDual(-table.data[pivotRow][len(table.data[pivotRow])-1])
+	return step
+}
+
+// driveOutStep returns the pivot that replaces the artificial variable of the row by the column after Phase I.
+func (table *SimplexTable) driveOutStep(row, col int) TraceStep {
+	step := TraceStep{
+		Entering:     table.columnName(col),
+		Leaving:      table.columnName(int(table.basicVariables[row])),
+		PivotRow:     row,
+		PivotColumn:  col,
+		PivotElement: table.data[row][col],
+		ReducedCost:  table.data[len(table.data)-1][col],
+	}
+	step.Explanation = step.explainDriveOut()
+	return step
+}
@@ -0,0 +1,188 @@
+package solver
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestSolveWithOptions_Trace(t *testing.T) {
+	result, err := SolveWithOptions(context.Background(), parametricProblem(), SolveOptions{Trace: true})
+	if err != nil {
+		t.Fatalf("SolveWithOptions() error = %v", err)
+	}
+	trace := result.Trace
+	if trace == nil || len(trace.Steps) != result.Iterations {
+		t.Fatalf("Expected one step per iteration, got %+v for %d iterations", trace, result.Iterations)
+	}
+
+	// Bland's rule: x has the first negative reduced cost, s1 the smallest ratio 4 = 4/1
+	first := trace.Steps[0]
+	expected := TraceStep{
+		Iteration:    1,
+		Phase:        TracePhaseTwo,
+		Entering:     "x",
+		Leaving:      "s1",
+		PivotRow:     0,
+		PivotColumn:  0,
+		PivotElement: 1,
+		ReducedCost:  -3,
+		Rule:         "Bland",
+		Ratios: []TraceRatio{
+			{Variable: "s1", Numerator: 4, Denominator: 1, Ratio: 4},
+			{Variable: "s3", Numerator: 18, Denominator: 3, Ratio: 6},
+		},
+		Explanation: "x enters because its reduced cost -3 is the first negative one; s1 leaves with ratio 4 = 4/1, the smallest.",
+		Tableau: &TableauSnapshot{
+			Columns: []string{"x", "y", "s1", "s2", "s3", "RHS"},
+			Basis:   []string{"s1", "s2", "s3"},
+			Rows: [][]float64{
+				{1, 0, 1, 0, 0, 4},
+				{0, 2, 0, 1, 0, 12},
+				{3, 2, 0, 0, 1, 18},
+				{-3, -5, 0, 0, 0, 0},
+			},
+		},
+	}
+	if !reflect.DeepEqual(first, expected) {
+		t.Errorf("Expected the first step %+v, got %+v", expected, first)
+	}
+
+	// Every step starts from the tableau the previous one pivoted to
+	for k := 1; k < len(trace.Steps); k++ {
+		previous := trace.Steps[k-1]
+		if basis := trace.Steps[k].Tableau.Basis; basis[previous.PivotRow] != previous.Entering {
+			t.Errorf("Expected %s to be basic after step %d, got %v", previous.Entering, previous.Iteration, basis)
+		}
+	}
+	if got := trace.Final.Rows[3][5]; got != 36 || !reflect.DeepEqual(trace.Final.Basis, []string{"x", "s1", "y"}) {
+		t.Errorf("Expected the final tableau to hold the optimum 36, got\n%s", trace.Final)
+	}
+
+	text := trace.String()
+	for _, line := range []string{
+		"Iteration 1 (Phase II)",
+		"Basis     x     y   s1   s2   s3   RHS",
+		"s1     1.00  0.00 1.00 0.00 0.00  4.00",
+		"Entering: x (reduced cost -3.00)",
+		"Ratios: s1 4.00 / 1.00 = 4.00, s3 18.00 / 3.00 = 6.00",
+		"Leaving: s1 (pivot element 1.00)",
+		"Final tableau",
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("Expected the line %q in the trace:\n%s", line, text)
+		}
+	}
+
+	jsonString, err := trace.GetJSON()
+	if err != nil {
+		t.Fatalf("GetJSON() error = %v", err)
+	}
+	var decoded Trace
+	if err := json.Unmarshal([]byte(jsonString), &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, trace) {
+		t.Errorf("Expected the JSON to hold the trace, got %s", jsonString)
+	}
+}
+
+func TestSolveWithOptions_TracePhases(t *testing.T) {
+	// 2y >= 12 makes the slack basis infeasible
+	phaseOne := parametricProblem()
+	phaseOne.Comparisons[1] = model.BE
+
+	// min x + y s.t. x + y >= 2 starts dual feasible
+	dual := &model.LinearProgram{
+		NbConstraints:   1,
+		NbVariables:     2,
+		VariableNames:   []string{"x", "y"},
+		Objective:       model.MINIMIZE,
+		ObjCoeff:        []float64{1, 1},
+		Comparisons:     []model.Comparison{model.BE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}}),
+		Rhs:             []float64{2},
+	}
+
+	unbounded := parametricProblem()
+	unbounded.Comparisons[1], unbounded.Comparisons[2] = model.BE, model.BE
+
+	tests := []struct {
+		name   string
+		lp     *model.LinearProgram
+		opts   SolveOptions
+		phases []string
+		last   string
+	}{
+		{"PhaseOne", phaseOne, SolveOptions{}, []string{TracePhaseOne, TracePhaseTwo}, "Leaving: x (pivot element 0.33)"},
+		{"BigM", phaseOne, SolveOptions{Initialization: BigM}, []string{TraceBigM}, "Leaving: x (pivot element 0.33)"},
+		{"DualSimplex", dual, SolveOptions{Algorithm: DualSimplex}, []string{TraceDualSimplex}, "Leaving: s1 (pivot element -1.00)"},
+		{"Unbounded", unbounded, SolveOptions{}, []string{TracePhaseOne, TracePhaseTwo}, "No variable leaves"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Trace = true
+			result, _ := SolveWithOptions(context.Background(), tt.lp, opts)
+			if result == nil || result.Trace == nil || len(result.Trace.Steps) == 0 {
+				t.Fatalf("Expected a trace, got %+v", result)
+			}
+
+			var phases []string
+			for _, step := range result.Trace.Steps {
+				if len(phases) == 0 || phases[len(phases)-1] != step.Phase {
+					phases = append(phases, step.Phase)
+				}
+			}
+			if !reflect.DeepEqual(phases, tt.phases) {
+				t.Errorf("Expected the phases %v, got %v", tt.phases, phases)
+			}
+
+			last := result.Trace.Steps[len(result.Trace.Steps)-1]
+			if description := last.describe(); !strings.Contains(description, tt.last+"\n") {
+				t.Errorf("Expected the last step to end with %q, got:\n%s", tt.last, description)
+			}
+		})
+	}
+}
+
+func TestSolveWithOptions_TraceUnsupported(t *testing.T) {
+	for _, algorithm := range []Algorithm{RevisedSimplex, InteriorPoint} {
+		result, err := SolveWithOptions(context.Background(), parametricProblem(), SolveOptions{Algorithm: algorithm, Trace: true})
+		if err == nil || result != nil {
+			t.Errorf("Expected an error for a trace of algorithm %v, got %+v", algorithm, result)
+		}
+	}
+
+	// Automatic keeps the tableau simplex to record the trace
+	result, err := SolveWithOptions(context.Background(), parametricProblem(), SolveOptions{Trace: true})
+	if err != nil || len(result.Trace.Steps) == 0 {
+		t.Errorf("Expected a trace from Automatic, got %v", err)
+	}
+}
+
+func TestSimplexTable_String(t *testing.T) {
+	var table SimplexTable
+	table.InitializeTableau(parametricProblem())
+
+	expected := "Simplex Tableau:\n" +
+		"Basis     x     y   s1   s2   s3   RHS\n" +
+		"s1     1.00  0.00 1.00 0.00 0.00  4.00\n" +
+		"s2     0.00  2.00 0.00 1.00 0.00 12.00\n" +
+		"s3     3.00  2.00 0.00 0.00 1.00 18.00\n" +
+		"z     -3.00 -5.00 0.00 0.00 0.00  0.00\n"
+	if got := table.String(); got != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+	}
+
+	// Tables built without a problem fall back to generic names
+	table = SimplexTable{data: [][]float64{{1, 1, 4}, {-1, 0, 0}}, basicVariables: []float64{1}}
+	if got := table.Snapshot().Columns; !reflect.DeepEqual(got, []string{"x1", "x2", "RHS"}) {
+		t.Errorf("Expected the columns [x1 x2 RHS], got %v", got)
+	}
+}
@@ -0,0 +1,149 @@
+package solver
+
+import "math"
+
+// AddArtificialVariables negates every constraint row with a negative RHS and gives it an
+// artificial variable, so that the artificial and slack variables form a feasible basis.
+func (table *SimplexTable) AddArtificialVariables() {
+	numConstraintRows := len(table.data) - 1
+	rhsCol := len(table.data[0]) - 1
+
+	var rows []int
+	for i := 0; i < numConstraintRows; i++ {
+		if table.data[i][rhsCol] < 0 {
+			rows = append(rows, i)
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	// Insert one column per artificial variable just before the RHS column
+	numCols := rhsCol + len(rows) + 1
+	for i := range table.data {
+		row := make([]float64, numCols)
+		copy(row, table.data[i][:rhsCol])
+		row[numCols-1] = table.data[i][rhsCol]
+		table.data[i] = row
+	}
+	if table.upperBounds != nil {
+		for range rows {
+			table.upperBounds = append(table.upperBounds, math.Inf(1))
+			table.atUpper = append(table.atUpper, false)
+		}
+	}
+
+	for k, i := range rows {
+		col := rhsCol + k
+		for j := range table.data[i] {
+			table.data[i][j] *= -1
+		}
+		table.data[i][col] = 1
+		table.basicVariables[i] = float64(col)
+		table.artificials = append(table.artificials, col)
+	}
+}
+
+// PhaseOne finds a feasible basis by minimizing the sum of the artificial variables.
+// On success the original objective row is restored, so Phase II can continue on the same tableau.
+func (table *SimplexTable) PhaseOne() error {
+	table.AddArtificialVariables()
+	table.phaseTwoObjective = table.data[len(table.data)-1]
+	return table.minimizeArtificials()
+}
+
+// minimizeArtificials optimizes the Phase I objective from the current basis, then drives the artificial
+// variables out of the basis and restores the objective row saved in phaseTwoObjective if their sum reached zero.
+func (table *SimplexTable) minimizeArtificials() error {
+	objectiveRow := len(table.data) - 1
+	rhsCol := len(table.data[0]) - 1
+	epsilon := 1e-9
+
+	table.setPhaseOneObjective()
+	table.phase = TracePhaseOne
+
+	err := table.optimize()
+	if err != nil {
+		return err
+	}
+
+	if table.data[objectiveRow][rhsCol] < -epsilon {
+		return table.infeasibleError()
+	}
+
+	table.driveOutArtificials()
+
+	table.data[objectiveRow] = table.phaseTwoObjective
+	table.phaseTwoObjective = nil
+	table.priceOut()
+	table.phase = TracePhaseTwo
+
+	return nil
+}
+
+// setPhaseOneObjective replaces the objective row with the Phase I objective, maximize -(a1 + a2 + ...).
+func (table *SimplexTable) setPhaseOneObjective() {
+	objectiveRow := len(table.data) - 1
+
+	table.data[objectiveRow] = make([]float64, len(table.data[0]))
+	for _, col := range table.artificials {
+		table.data[objectiveRow][col] = 1
+	}
+	table.priceOut()
+}
+
+// infeasibleError returns the error for a Phase I optimum below zero. The Phase I duals are a Farkas
+// certificate for the rows of the slack form: combined with them, the constraints cannot be satisfied
+// by any variables within their bounds.
+func (table *SimplexTable) infeasibleError() error {
+	return &InfeasibleError{Farkas: table.rowMultipliers(len(table.data) - 1)}
+}
+
+// priceOut eliminates the basic variables from the objective row,
+// so that it holds the reduced costs of the current basis.
+func (table *SimplexTable) priceOut() {
+	objectiveRow := len(table.data) - 1
+
+	for i, basic := range table.basicVariables {
+		factor := table.data[objectiveRow][int(basic)]
+		if factor == 0 {
+			continue
+		}
+		for j := range table.data[objectiveRow] {
+			table.data[objectiveRow][j] -= factor * table.data[i][j]
+		}
+	}
+}
+
+// driveOutArtificials pivots the artificial variables that are still basic (at zero level)
+// out of the basis. Rows where this is impossible are redundant and keep their artificial variable.
+func (table *SimplexTable) driveOutArtificials() {
+	rhsCol := len(table.data[0]) - 1
+	epsilon := 1e-10
+
+	for i, basic := range table.basicVariables {
+		if !table.isArtificial(int(basic)) {
+			continue
+		}
+		for j := 0; j < rhsCol; j++ {
+			if !table.isArtificial(j) && math.Abs(table.data[i][j]) > epsilon {
+				if table.trace != nil {
+					table.record(table.driveOutStep(i, j))
+				}
+				table.PerformPivot(i, j)
+				table.basicVariables[i] = float64(j)
+				break
+			}
+		}
+	}
+}
+
+// isArtificial reports whether the column holds an artificial variable.
+func (table *SimplexTable) isArtificial(col int) bool {
+	for _, artificial := range table.artificials {
+		if artificial == col {
+			return true
+		}
+	}
+	return false
+}
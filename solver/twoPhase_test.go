@@ -0,0 +1,103 @@
+package solver
+
+import (
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+func TestSolve_GreaterOrEqualConstraint(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints: 2,
+		NbVariables:   2,
+		VariableNames: []string{"x", "y"},
+		Objective:     model.MINIMIZE,
+		ObjCoeff:      []float64{2, 3},
+		Comparisons:   []model.Comparison{model.BE, model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 1},
+			{2, 1},
+		}),
+		Rhs: []float64{10, 20},
+	}
+
+	result, err := Solve(lp)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+
+	// Expected solution for this problem is x=10, y=0, objective=20
+	expectedSolution := []float64{10, 0, 20}
+	if !equalFloat64Slices(result.ObjVar, expectedSolution, 1e-9) {
+		t.Errorf("Expected solution to be %v, got %v", expectedSolution, result.ObjVar)
+	}
+}
+
+func TestSolve_EqualityAndNegativeRhs(t *testing.T) {
+	lp := &model.LinearProgram{
+		NbConstraints: 3,
+		NbVariables:   2,
+		VariableNames: []string{"x", "y"},
+		Objective:     model.MAXIMIZE,
+		ObjCoeff:      []float64{1, 1},
+		Comparisons:   []model.Comparison{model.EQ, model.LE, model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 2},
+			{1, 0},
+			{-1, -1},
+		}),
+		Rhs: []float64{4, 3, -1},
+	}
+
+	result, err := Solve(lp)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+
+	// Expected solution for this problem is x=3, y=0.5, objective=3.5
+	expectedSolution := []float64{3, 0.5, 3.5}
+	if !equalFloat64Slices(result.ObjVar, expectedSolution, 1e-9) {
+		t.Errorf("Expected solution to be %v, got %v", expectedSolution, result.ObjVar)
+	}
+}
+
+func TestPhaseOne(t *testing.T) {
+	t.Run("Feasible", func(t *testing.T) {
+		// x + y >= 2 written as -x - y + s1 = -2, x <= 3
+		table := &SimplexTable{
+			data: [][]float64{
+				{-1, -1, 1, 0, -2},
+				{1, 0, 0, 1, 3},
+				{-1, -1, 0, 0, 0},
+			},
+			basicVariables: []float64{2, 3},
+		}
+		if err := table.PhaseOne(); err != nil {
+			t.Fatalf("PhaseOne() error = %v", err)
+		}
+		if !table.IsInitiallyFeasible() {
+			t.Errorf("Expected a feasible basis after Phase I, got %v", table.data)
+		}
+		for _, basic := range table.basicVariables {
+			if table.isArtificial(int(basic)) {
+				t.Errorf("Expected artificial variables to leave the basis, got basis %v", table.basicVariables)
+			}
+		}
+	})
+
+	t.Run("Infeasible", func(t *testing.T) {
+		// x + y >= 2 and x + y <= 1
+		table := &SimplexTable{
+			data: [][]float64{
+				{-1, -1, 1, 0, -2},
+				{1, 1, 0, 1, 1},
+				{-1, -1, 0, 0, 0},
+			},
+			basicVariables: []float64{2, 3},
+		}
+		err := table.PhaseOne()
+		if err == nil || err.Error() != "infeasible problem" {
+			t.Errorf("Expected error to be 'infeasible problem', got %v", err)
+		}
+	})
+}
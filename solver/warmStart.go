@@ -0,0 +1,185 @@
+package solver
+
+import (
+	"math"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// Basis returns the current basis of the tableau. An artificial variable left basic on a row where no other
+// column could replace it is replaced by a nonbasic column, see replacementColumn.
+func (table *SimplexTable) Basis() *model.Basis {
+	firstSlack := table.firstSlack()
+	n := firstSlack + len(table.basicVariables)
+	basis := &model.Basis{
+		BasicVariables: make([]int, len(table.basicVariables)),
+		AtUpper:        make([]bool, n),
+	}
+
+	basic := make([]bool, n)
+	for _, col := range table.basicVariables {
+		if int(col) < n {
+			basic[int(col)] = true
+		}
+	}
+	for i, col := range table.basicVariables {
+		basicCol := int(col)
+		if basicCol >= n {
+			basicCol = replacementColumn(firstSlack+i, basic, func(j int) float64 { return table.data[i][j] })
+			basic[basicCol] = true
+		}
+		basis.BasicVariables[i] = basicCol
+	}
+	for j := range basis.AtUpper {
+		basis.AtUpper[j] = !basic[j] && table.atUpper[j]
+	}
+	return basis
+}
+
+// WarmStart replaces the slack basis of a freshly initialized tableau with the given basis. The nonbasic
+// columns at their upper bound are complemented and the basic columns are pivoted in. Basic variables above
+// their upper bound are complemented as well, so that every bound violation shows as a negative RHS that
+// Phase I, Big-M or the dual simplex can repair. It returns false if the basis does not fit the tableau or is
+// singular, in which case the tableau has to be initialized again.
+func (table *SimplexTable) WarmStart(basis *model.Basis) bool {
+	rhsCol := len(table.data[0]) - 1
+	epsilon := 1e-9
+
+	if len(basis.BasicVariables) != len(table.basicVariables) || len(basis.AtUpper) != rhsCol || len(table.artificials) > 0 {
+		return false
+	}
+	wanted := make([]bool, rhsCol)
+	for _, col := range basis.BasicVariables {
+		if col < 0 || col >= rhsCol || wanted[col] {
+			return false
+		}
+		wanted[col] = true
+	}
+
+	for j, atUpper := range basis.AtUpper {
+		if atUpper && !wanted[j] && !math.IsInf(table.upperBound(j), 1) {
+			table.complement(j)
+		}
+	}
+
+	basic := make([]bool, rhsCol)
+	for _, col := range table.basicVariables {
+		basic[int(col)] = true
+	}
+	for _, col := range basis.BasicVariables {
+		if basic[col] {
+			continue
+		}
+		// Replace the unwanted basic variable with the largest entry in the column
+		pivotRow := -1
+		largest := epsilon
+		for i, leaving := range table.basicVariables {
+			if !wanted[int(leaving)] && math.Abs(table.data[i][col]) > largest {
+				largest = math.Abs(table.data[i][col])
+				pivotRow = i
+			}
+		}
+		if pivotRow == -1 {
+			return false
+		}
+		basic[int(table.basicVariables[pivotRow])] = false
+		table.PerformPivot(pivotRow, col)
+		table.basicVariables[pivotRow] = float64(col)
+		basic[col] = true
+	}
+
+	for i, col := range table.basicVariables {
+		if table.data[i][rhsCol] > table.upperBound(int(col))+epsilon {
+			table.complementBasic(i)
+		}
+	}
+	return true
+}
+
+// Basis returns the current basis of the revised simplex. An artificial variable left basic on a row where no
+// other column could replace it is replaced by a nonbasic column, see replacementColumn.
+func (rs *RevisedSimplexSolver) Basis() *model.Basis {
+	firstSlack := rs.numCols - rs.numRows
+	basis := &model.Basis{
+		BasicVariables: make([]int, rs.numRows),
+		AtUpper:        make([]bool, rs.numCols),
+	}
+
+	basic := make([]bool, rs.numCols)
+	for _, col := range rs.basis {
+		if !rs.isArtificial(col) {
+			basic[col] = true
+		}
+	}
+	for i, col := range rs.basis {
+		if rs.isArtificial(col) {
+			unit := make([]float64, rs.numRows)
+			unit[i] = 1
+			rowOfInverse := rs.Btran(unit)
+			col = replacementColumn(firstSlack+i, basic, func(j int) float64 { return rs.columns[j].Dot(rowOfInverse) })
+			basic[col] = true
+		}
+		basis.BasicVariables[i] = col
+	}
+	for j := range basis.AtUpper {
+		basis.AtUpper[j] = !basic[j] && rs.atUpper[j]
+	}
+	return basis
+}
+
+// replacementColumn returns the nonbasic column that replaces an artificial variable left basic on a row in a
+// saved basis: the one with the largest entry in the row of the tableau, given by entry, and the slack of the
+// row when it is nonbasic and no entry is larger. A column that is already basic in another row would make
+// WarmStart reject the whole basis.
+func replacementColumn(slack int, basic []bool, entry func(int) float64) int {
+	best, largest := -1, 0.0
+	if !basic[slack] {
+		best, largest = slack, math.Abs(entry(slack))
+	}
+	for j := range basic {
+		if !basic[j] && (best == -1 || math.Abs(entry(j)) > largest) {
+			best, largest = j, math.Abs(entry(j))
+		}
+	}
+	return best
+}
+
+// WarmStart replaces the starting basis built by Initialize with the given basis, dropping the artificial
+// variables. The revised simplex has no Phase I from an arbitrary basis, so it returns false when the basis
+// does not fit the problem, is singular or is not primal feasible. The solver then has to be initialized again.
+func (rs *RevisedSimplexSolver) WarmStart(basis *model.Basis) bool {
+	epsilon := 1e-9
+
+	if len(basis.BasicVariables) != rs.numRows || len(basis.AtUpper) != rs.numCols {
+		return false
+	}
+
+	rs.artificialRows = nil
+	rs.cost = rs.cost[:rs.numCols]
+	rs.basicRow = rs.basicRow[:rs.numCols]
+	rs.upper = rs.upper[:rs.numCols]
+	rs.atUpper = rs.atUpper[:rs.numCols]
+	for j := range rs.basicRow {
+		rs.basicRow[j] = -1
+	}
+	for i, col := range basis.BasicVariables {
+		if col < 0 || col >= rs.numCols || rs.basicRow[col] != -1 {
+			return false
+		}
+		rs.basis[i] = col
+		rs.basicRow[col] = i
+	}
+	for j, atUpper := range basis.AtUpper {
+		rs.atUpper[j] = atUpper && rs.basicRow[j] == -1 && !math.IsInf(rs.upper[j], 1)
+	}
+
+	if rs.Refactorize() != nil {
+		return false
+	}
+	for i, col := range rs.basis {
+		if rs.values[i] < -epsilon || rs.values[i] > rs.upper[col]+epsilon {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,158 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/Chemberlein/LinearProgrammingTools/model"
+)
+
+// warmStartProblem builds maximize c*x subject to x1 <= b1, 2x2 <= b2, 3x1 + 2x2 <= b3 and x2 + x3 >= b4, x3 <= 2.
+func warmStartProblem(objCoeff, rhs []float64) *model.LinearProgram {
+	inf := math.Inf(1)
+	return &model.LinearProgram{
+		NbConstraints: 4,
+		NbVariables:   3,
+		VariableNames: []string{"x1", "x2", "x3"},
+		Objective:     model.MAXIMIZE,
+		ObjCoeff:      objCoeff,
+		Comparisons:   []model.Comparison{model.LE, model.LE, model.LE, model.BE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{
+			{1, 0, 0},
+			{0, 2, 0},
+			{3, 2, 0},
+			{0, 1, 1},
+		}),
+		Rhs:         rhs,
+		UpperBounds: []float64{inf, inf, 2},
+	}
+}
+
+func TestWarmStart_SameProblem(t *testing.T) {
+	lp := warmStartProblem([]float64{3, 5, 1}, []float64{4, 12, 18, 1})
+	result, err := Solve(lp)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if result.Basis == nil {
+		t.Fatalf("Expected the final basis to be exported")
+	}
+
+	var table SimplexTable
+	table.InitializeTableau(warmStartProblem([]float64{3, 5, 1}, []float64{4, 12, 18, 1}))
+	if !table.WarmStart(result.Basis) {
+		t.Fatalf("Expected WarmStart() to accept the basis")
+	}
+	if !table.IsInitiallyFeasible() || table.FindEnteringVariable() != -1 {
+		t.Errorf("Expected the saved basis to be optimal right away:\n%s", table.String())
+	}
+}
+
+func TestWarmStart_ModifiedProblem(t *testing.T) {
+	tests := []struct {
+		name     string
+		objCoeff []float64
+		rhs      []float64
+	}{
+		{"Unchanged", []float64{3, 5, 1}, []float64{4, 12, 18, 1}},
+		{"ObjectiveChange", []float64{4, 3, 1}, []float64{4, 12, 18, 1}},
+		{"RhsChangeKeepsBasisFeasible", []float64{3, 5, 1}, []float64{4, 12, 16, 1}},
+		{"RhsChangeNeedsDualSimplex", []float64{3, 5, 1}, []float64{4, 20, 18, 1}},
+		{"BothChange", []float64{9, 1, -1}, []float64{4, 20, 18, 2}},
+	}
+	algorithms := map[string]SolveOptions{
+		"PrimalSimplex":  {Algorithm: PrimalSimplex},
+		"BigM":           {Algorithm: PrimalSimplex, Initialization: BigM},
+		"Devex":          {Algorithm: PrimalSimplex, Pricing: DevexPricing},
+		"RevisedSimplex": {Algorithm: RevisedSimplex},
+	}
+
+	for _, tt := range tests {
+		for name, opts := range algorithms {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				saved := warmStartProblem([]float64{3, 5, 1}, []float64{4, 12, 18, 1})
+				savedResult, err := SolveWithOptions(context.Background(), saved, opts)
+				if err != nil {
+					t.Fatalf("SolveWithOptions() error = %v", err)
+				}
+
+				cold := warmStartProblem(tt.objCoeff, tt.rhs)
+				coldResult, err := SolveWithOptions(context.Background(), cold, opts)
+				if err != nil {
+					t.Fatalf("SolveWithOptions() error = %v", err)
+				}
+
+				warm := warmStartProblem(tt.objCoeff, tt.rhs)
+				opts.WarmStart = savedResult.Basis
+				warmResult, err := SolveWithOptions(context.Background(), warm, opts)
+				if err != nil {
+					t.Fatalf("SolveWithOptions() with warm start error = %v", err)
+				}
+				if !equalFloat64Slices(warmResult.ObjVar, coldResult.ObjVar, 1e-6) {
+					t.Errorf("Expected %v, got %v", coldResult.ObjVar, warmResult.ObjVar)
+				}
+				if !equalFloat64Slices(warmResult.Duals, coldResult.Duals, 1e-6) {
+					t.Errorf("Expected duals %v, got %v", coldResult.Duals, warmResult.Duals)
+				}
+			})
+		}
+	}
+}
+
+func TestWarmStart_Fallbacks(t *testing.T) {
+	saved, err := Solve(warmStartProblem([]float64{3, 5, 1}, []float64{4, 12, 18, 1}))
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+
+	// A basis of another shape falls back to the slack basis
+	lp := &model.LinearProgram{
+		NbConstraints:   1,
+		NbVariables:     2,
+		VariableNames:   []string{"x1", "x2"},
+		Objective:       model.MAXIMIZE,
+		ObjCoeff:        []float64{1, 2},
+		Comparisons:     []model.Comparison{model.LE},
+		ConstraintCoeff: model.NewSparseMatrixFromDense([][]float64{{1, 1}}),
+		Rhs:             []float64{4},
+	}
+	result, err := SolveWithOptions(context.Background(), lp, SolveOptions{WarmStart: saved.Basis})
+	if err != nil {
+		t.Fatalf("SolveWithOptions() error = %v", err)
+	}
+	if !equalFloat64Slices(result.ObjVar, []float64{0, 4, 8}, 1e-9) {
+		t.Errorf("Expected [0 4 8], got %v", result.ObjVar)
+	}
+
+	// A warm start of an infeasible problem still proves its infeasibility
+	infeasible := warmStartProblem([]float64{3, 5, 1}, []float64{4, 12, 18, 9})
+	original := infeasible.Clone()
+	_, err = SolveWithOptions(context.Background(), infeasible, SolveOptions{WarmStart: saved.Basis})
+	var infeasibleErr *InfeasibleError
+	if !errors.As(err, &infeasibleErr) {
+		t.Fatalf("Expected an InfeasibleError, got %v", err)
+	}
+	if err := CheckCertificate(original, err); err != nil {
+		t.Errorf("CheckCertificate() error = %v", err)
+	}
+}
+
+func TestSimplexTable_BasisWithArtificial(t *testing.T) {
+	// The artificial variable a stays basic on the second row, whose slack s2 is already basic on the first row
+	table := &SimplexTable{
+		data: [][]float64{
+			{0.5, 0, 1, 0, 3},
+			{2e-12, 0, 0, 1, 0},
+			{1, 0, 0, 0, 6},
+		},
+		basicVariables: []float64{2, 3},
+		artificials:    []int{3},
+		atUpper:        make([]bool, 4),
+	}
+	basis := table.Basis()
+	if basis.BasicVariables[0] != 2 || basis.BasicVariables[1] != 0 {
+		t.Errorf("Expected the basis [2 0], with x replacing a instead of the basic s2, got %v", basis.BasicVariables)
+	}
+}